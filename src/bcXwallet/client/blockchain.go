@@ -6,14 +6,99 @@ import (
 	rpcclient "common/rpc/lib/client"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 )
 
+// chain - name of chain profile that requests are for, empty means the default chain of wallet
+var chain string
+
+// SetChain - set chain profile of all requests
+func SetChain(name string) {
+	chain = name
+}
+
+func withChain(params map[string]interface{}) map[string]interface{} {
+	if chain != "" {
+		params["chain"] = chain
+	}
+
+	return params
+}
+
+func Status(url string) (err error) {
+
+	rpc := rpcclient.NewJSONRPCClientEx(url, "", true)
+
+	result := new(rpc3.StatusResult)
+	_, err = rpc.Call("bcb_status", withChain(map[string]interface{}{}), result)
+	if err != nil {
+		fmt.Printf("Cannot get status, error=%s \n", err.Error())
+		return nil
+	}
+
+	jsIndent, _ := json.MarshalIndent(result, "", "  ")
+	fmt.Println(string(jsIndent))
+
+	return
+}
+
+func ChainVersion(url string) (err error) {
+
+	rpc := rpcclient.NewJSONRPCClientEx(url, "", true)
+
+	result := new(rpc3.ChainVersionResult)
+	_, err = rpc.Call("bcb_chainVersion", withChain(map[string]interface{}{}), result)
+	if err != nil {
+		fmt.Printf("Cannot get chain version, error=%s \n", err.Error())
+		return nil
+	}
+
+	jsIndent, _ := json.MarshalIndent(result, "", "  ")
+	fmt.Println(string(jsIndent))
+
+	return
+}
+
+func Validators(height int64, url string) (err error) {
+
+	rpc := rpcclient.NewJSONRPCClientEx(url, "", true)
+
+	result := new(rpc3.ValidatorsResult)
+	_, err = rpc.Call("bcb_validators", withChain(map[string]interface{}{"height": height}), result)
+	if err != nil {
+		fmt.Printf("Cannot get validators, height=%d, error=%s \n", height, err.Error())
+		return nil
+	}
+
+	jsIndent, _ := json.MarshalIndent(result, "", "  ")
+	fmt.Println(string(jsIndent))
+
+	return
+}
+
+func NetInfo(url string) (err error) {
+
+	rpc := rpcclient.NewJSONRPCClientEx(url, "", true)
+
+	result := new(rpc3.NetInfoResult)
+	_, err = rpc.Call("bcb_netInfo", withChain(map[string]interface{}{}), result)
+	if err != nil {
+		fmt.Printf("Cannot get net info, error=%s \n", err.Error())
+		return nil
+	}
+
+	jsIndent, _ := json.MarshalIndent(result, "", "  ")
+	fmt.Println(string(jsIndent))
+
+	return
+}
+
 func BlockHeight(url string) (err error) {
 
 	rpc := rpcclient.NewJSONRPCClientEx(url, "", true)
 
 	result := new(rpc3.BlockHeightResult)
-	_, err = rpc.Call("bcb_blockHeight", map[string]interface{}{}, result)
+	_, err = rpc.Call("bcb_blockHeight", withChain(map[string]interface{}{}), result)
 	if err != nil {
 		fmt.Printf("Cannot get block height, error=%s \n", err.Error())
 		return nil
@@ -25,12 +110,12 @@ func BlockHeight(url string) (err error) {
 	return
 }
 
-func Block(height int64, url string) (err error) {
+func Block(height int64, amountUnit, url string) (err error) {
 
 	rpc := rpcclient.NewJSONRPCClientEx(url, "", true)
 
 	result := new(rpc3.BlockResult)
-	_, err = rpc.Call("bcb_block", map[string]interface{}{"height": height}, result)
+	_, err = rpc.Call("bcb_block", withChain(map[string]interface{}{"height": height, "amountUnit": amountUnit}), result)
 	if err != nil {
 		fmt.Printf("Cannot get block data, height=%d, error=%s \n", height, err.Error())
 		return nil
@@ -42,7 +127,24 @@ func Block(height int64, url string) (err error) {
 	return
 }
 
-func Transaction(txHash, url string) (err error) {
+func Blocks(minHeight, maxHeight int64, detail bool, amountUnit, url string) (err error) {
+
+	rpc := rpcclient.NewJSONRPCClientEx(url, "", true)
+
+	result := new(rpc3.BlocksResult)
+	_, err = rpc.Call("bcb_blocks", withChain(map[string]interface{}{"minHeight": minHeight, "maxHeight": maxHeight, "detail": detail, "amountUnit": amountUnit}), result)
+	if err != nil {
+		fmt.Printf("Cannot get blocks data, minHeight=%d, maxHeight=%d, error=%s \n", minHeight, maxHeight, err.Error())
+		return nil
+	}
+
+	jsIndent, _ := json.MarshalIndent(result, "", "  ")
+	fmt.Println(string(jsIndent))
+
+	return
+}
+
+func Transaction(txHash, amountUnit, url string) (err error) {
 
 	if txHash[:2] == "0x" {
 		txHash = txHash[2:]
@@ -50,7 +152,7 @@ func Transaction(txHash, url string) (err error) {
 	rpc := rpcclient.NewJSONRPCClientEx(url, "", true)
 
 	result := new(rpc3.TxResult)
-	_, err = rpc.Call("bcb_transaction", map[string]interface{}{"txHash": txHash}, result)
+	_, err = rpc.Call("bcb_transaction", withChain(map[string]interface{}{"txHash": txHash, "amountUnit": amountUnit}), result)
 	if err != nil {
 		fmt.Printf("Cannot get transaction, txHash=%s, error=%s \n", txHash, err.Error())
 		return nil
@@ -62,12 +164,29 @@ func Transaction(txHash, url string) (err error) {
 	return
 }
 
-func Balance(address keys.Address, url string) (err error) {
+func PendingTxs(address, url string) (err error) {
+
+	rpc := rpcclient.NewJSONRPCClientEx(url, "", true)
+
+	result := new(rpc3.PendingTxsResult)
+	_, err = rpc.Call("bcb_pendingTxs", withChain(map[string]interface{}{"address": address}), result)
+	if err != nil {
+		fmt.Printf("Cannot get pending transactions, address=%s, error=%s \n", address, err.Error())
+		return nil
+	}
+
+	jsIndent, _ := json.MarshalIndent(result, "", "  ")
+	fmt.Println(string(jsIndent))
+
+	return
+}
+
+func Balance(address keys.Address, amountUnit string, height int64, url string) (err error) {
 
 	rpc := rpcclient.NewJSONRPCClientEx(url, "", true)
 
 	result := new(rpc3.BalanceResult)
-	_, err = rpc.Call("bcb_balance", map[string]interface{}{"address": address}, result)
+	_, err = rpc.Call("bcb_balance", withChain(map[string]interface{}{"address": address, "amountUnit": amountUnit, "height": height}), result)
 	if err != nil {
 		fmt.Printf("Cannot get balance, address=%s, error=%s \n", address, err.Error())
 		return nil
@@ -79,12 +198,12 @@ func Balance(address keys.Address, url string) (err error) {
 	return
 }
 
-func BalanceOfToken(address, tokenAddress keys.Address, tokenName string, url string) (err error) {
+func BalanceOfToken(address, tokenAddress keys.Address, tokenName, amountUnit string, height int64, url string) (err error) {
 
 	rpc := rpcclient.NewJSONRPCClientEx(url, "", true)
 
 	result := new(rpc3.BalanceResult)
-	_, err = rpc.Call("bcb_balanceOfToken", map[string]interface{}{"address": address, "tokenAddress": tokenAddress, "tokenName": tokenName}, result)
+	_, err = rpc.Call("bcb_balanceOfToken", withChain(map[string]interface{}{"address": address, "tokenAddress": tokenAddress, "tokenName": tokenName, "amountUnit": amountUnit, "height": height}), result)
 	if err != nil {
 		fmt.Printf("Cannot get balance of token, address=%s, tokenAddress=%s, error=%s \n", address, tokenAddress, err.Error())
 		return nil
@@ -96,12 +215,12 @@ func BalanceOfToken(address, tokenAddress keys.Address, tokenName string, url st
 	return
 }
 
-func AllBalance(address keys.Address, url string) (err error) {
+func AllBalance(address keys.Address, amountUnit string, height int64, url string) (err error) {
 
 	rpc := rpcclient.NewJSONRPCClientEx(url, "", true)
 
 	result := new([]rpc3.AllBalanceItemResult)
-	_, err = rpc.Call("bcb_allBalance", map[string]interface{}{"address": address}, result)
+	_, err = rpc.Call("bcb_allBalance", withChain(map[string]interface{}{"address": address, "amountUnit": amountUnit, "height": height}), result)
 	if err != nil {
 		fmt.Printf("Cannot all balance, address=%s, error=%s \n", address, err.Error())
 		return nil
@@ -113,12 +232,29 @@ func AllBalance(address keys.Address, url string) (err error) {
 	return
 }
 
-func Nonce(address keys.Address, url string) (err error) {
+func BalanceAtTime(address, token keys.Address, timestamp int64, amountUnit, url string) (err error) {
+
+	rpc := rpcclient.NewJSONRPCClientEx(url, "", true)
+
+	result := new(rpc3.BalanceAtTimeResult)
+	_, err = rpc.Call("bcb_balanceAtTime", withChain(map[string]interface{}{"address": address, "token": token, "timestamp": timestamp, "amountUnit": amountUnit}), result)
+	if err != nil {
+		fmt.Printf("Cannot get balance at time, address=%s, token=%s, timestamp=%d, error=%s \n", address, token, timestamp, err.Error())
+		return nil
+	}
+
+	jsIndent, _ := json.MarshalIndent(result, "", "  ")
+	fmt.Println(string(jsIndent))
+
+	return
+}
+
+func Nonce(address keys.Address, height int64, url string) (err error) {
 
 	rpc := rpcclient.NewJSONRPCClientEx(url, "", true)
 
 	result := new(rpc3.NonceResult)
-	_, err = rpc.Call("bcb_nonce", map[string]interface{}{"address": address}, result)
+	_, err = rpc.Call("bcb_nonce", withChain(map[string]interface{}{"address": address, "height": height}), result)
 	if err != nil {
 		fmt.Printf("Cannot get nonce, address=%s, error=%s \n", address, err.Error())
 		return nil
@@ -130,12 +266,97 @@ func Nonce(address keys.Address, url string) (err error) {
 	return
 }
 
-func CommitTx(tx, url string) (err error) {
+func Tokens(url string) (err error) {
+
+	rpc := rpcclient.NewJSONRPCClientEx(url, "", true)
+
+	result := new(rpc3.TokensResult)
+	_, err = rpc.Call("bcb_tokens", withChain(map[string]interface{}{}), result)
+	if err != nil {
+		fmt.Printf("Cannot get tokens, error=%s \n", err.Error())
+		return nil
+	}
+
+	jsIndent, _ := json.MarshalIndent(result, "", "  ")
+	fmt.Println(string(jsIndent))
+
+	return
+}
+
+func TokenInfo(tokenAddress keys.Address, tokenName, tokenSymbol, url string) (err error) {
+
+	rpc := rpcclient.NewJSONRPCClientEx(url, "", true)
+
+	result := new(rpc3.TokenInfoResult)
+	_, err = rpc.Call("bcb_tokenInfo", withChain(map[string]interface{}{"tokenAddress": tokenAddress, "tokenName": tokenName, "tokenSymbol": tokenSymbol}), result)
+	if err != nil {
+		fmt.Printf("Cannot get token info, tokenAddress=%s, tokenName=%s, tokenSymbol=%s, error=%s \n", tokenAddress, tokenName, tokenSymbol, err.Error())
+		return nil
+	}
+
+	jsIndent, _ := json.MarshalIndent(result, "", "  ")
+	fmt.Println(string(jsIndent))
+
+	return
+}
+
+func Contract(address keys.Address, height int64, url string) (err error) {
+
+	rpc := rpcclient.NewJSONRPCClientEx(url, "", true)
+
+	result := new(rpc3.ContractResult)
+	_, err = rpc.Call("bcb_contract", withChain(map[string]interface{}{"address": address, "height": height}), result)
+	if err != nil {
+		fmt.Printf("Cannot get contract, address=%s, height=%d, error=%s \n", address, height, err.Error())
+		return nil
+	}
+
+	jsIndent, _ := json.MarshalIndent(result, "", "  ")
+	fmt.Println(string(jsIndent))
+
+	return
+}
+
+func ContractVersions(orgID, name string, height int64, url string) (err error) {
+
+	rpc := rpcclient.NewJSONRPCClientEx(url, "", true)
+
+	result := new(rpc3.ContractVersionsResult)
+	_, err = rpc.Call("bcb_contractVersions", withChain(map[string]interface{}{"orgID": orgID, "name": name, "height": height}), result)
+	if err != nil {
+		fmt.Printf("Cannot get contract versions, orgID=%s, name=%s, height=%d, error=%s \n", orgID, name, height, err.Error())
+		return nil
+	}
+
+	jsIndent, _ := json.MarshalIndent(result, "", "  ")
+	fmt.Println(string(jsIndent))
+
+	return
+}
+
+func Organization(orgID, url string) (err error) {
+
+	rpc := rpcclient.NewJSONRPCClientEx(url, "", true)
+
+	result := new(rpc3.OrganizationResult)
+	_, err = rpc.Call("bcb_organization", withChain(map[string]interface{}{"orgID": orgID}), result)
+	if err != nil {
+		fmt.Printf("Cannot get organization, orgID=%s, error=%s \n", orgID, err.Error())
+		return nil
+	}
+
+	jsIndent, _ := json.MarshalIndent(result, "", "  ")
+	fmt.Println(string(jsIndent))
+
+	return
+}
+
+func CommitTx(tx, requestID, url string) (err error) {
 
 	rpc := rpcclient.NewJSONRPCClientEx(url, "", true)
 
 	result := new(rpc3.CommitTxResult)
-	_, err = rpc.Call("bcb_commitTx", map[string]interface{}{"tx": tx}, result)
+	_, err = rpc.Call("bcb_commitTx", withChain(map[string]interface{}{"tx": tx, "requestId": requestID}), result)
 	if err != nil {
 		fmt.Printf("Cannot commit transation, tx=%s, error=%s \n", tx, err.Error())
 		return nil
@@ -146,3 +367,188 @@ func CommitTx(tx, url string) (err error) {
 
 	return
 }
+
+func AddressHistory(address, token string, fromHeight, toHeight int64, cursor string, limit int, url string) (err error) {
+
+	rpc := rpcclient.NewJSONRPCClientEx(url, "", true)
+
+	result := new(rpc3.AddressHistoryResult)
+	params := map[string]interface{}{"address": address, "token": token, "fromHeight": fromHeight, "toHeight": toHeight, "cursor": cursor, "limit": limit}
+	_, err = rpc.Call("bcb_addressHistory", withChain(params), result)
+	if err != nil {
+		fmt.Printf("Cannot get address history, address=%s, error=%s \n", address, err.Error())
+		return nil
+	}
+
+	jsIndent, _ := json.MarshalIndent(result, "", "  ")
+	fmt.Println(string(jsIndent))
+
+	return
+}
+
+// Statement - get statement and write its content to file, print the content if file is empty
+func Statement(address, token string, fromTime, toTime int64, format, file, url string) (err error) {
+
+	rpc := rpcclient.NewJSONRPCClientEx(url, "", true)
+
+	result := new(rpc3.StatementResult)
+	params := map[string]interface{}{"address": address, "token": token, "fromTime": fromTime, "toTime": toTime, "format": format}
+	_, err = rpc.Call("bcb_statement", withChain(params), result)
+	if err != nil {
+		fmt.Printf("Cannot get statement, address=%s, error=%s \n", address, err.Error())
+		return nil
+	}
+
+	if file == "" {
+		fmt.Print(result.Content)
+		return
+	}
+
+	if err = ioutil.WriteFile(file, []byte(result.Content), 0600); err != nil {
+		fmt.Printf("Cannot write file %s, error=%s \n", file, err.Error())
+		return nil
+	}
+
+	result.Content = ""
+	jsIndent, _ := json.MarshalIndent(result, "", "  ")
+	fmt.Println(string(jsIndent))
+	fmt.Println("Export to " + file)
+
+	return
+}
+
+func ReconcileReport(height int64, url string) (err error) {
+
+	rpc := rpcclient.NewJSONRPCClientEx(url, "", true)
+
+	result := new(rpc3.ReconcileReportResult)
+	_, err = rpc.Call("bcb_reconcileReport", withChain(map[string]interface{}{"height": height}), result)
+	if err != nil {
+		fmt.Printf("Cannot get reconcile report, height=%d, error=%s \n", height, err.Error())
+		return nil
+	}
+
+	jsIndent, _ := json.MarshalIndent(result, "", "  ")
+	fmt.Println(string(jsIndent))
+
+	return
+}
+
+func Deposits(sinceID uint64, limit int, url string) (err error) {
+
+	rpc := rpcclient.NewJSONRPCClientEx(url, "", true)
+
+	result := new(rpc3.DepositsResult)
+	_, err = rpc.Call("bcb_deposits", withChain(map[string]interface{}{"sinceId": sinceID, "limit": limit}), result)
+	if err != nil {
+		fmt.Printf("Cannot get deposits, sinceId=%d, error=%s \n", sinceID, err.Error())
+		return nil
+	}
+
+	jsIndent, _ := json.MarshalIndent(result, "", "  ")
+	fmt.Println(string(jsIndent))
+
+	return
+}
+
+func DepositAck(id uint64, url string) (err error) {
+
+	rpc := rpcclient.NewJSONRPCClientEx(url, "", true)
+
+	result := new(rpc3.Deposit)
+	_, err = rpc.Call("bcb_depositAck", withChain(map[string]interface{}{"id": id}), result)
+	if err != nil {
+		fmt.Printf("Cannot ack deposit, id=%d, error=%s \n", id, err.Error())
+		return nil
+	}
+
+	jsIndent, _ := json.MarshalIndent(result, "", "  ")
+	fmt.Println(string(jsIndent))
+
+	return
+}
+
+func WebhookReplay(id uint64, url string) (err error) {
+
+	rpc := rpcclient.NewJSONRPCClientEx(url, "", true)
+
+	result := new(rpc3.WebhookEvent)
+	_, err = rpc.Call("bcb_webhookReplay", withChain(map[string]interface{}{"id": id}), result)
+	if err != nil {
+		fmt.Printf("Cannot replay webhook event, id=%d, error=%s \n", id, err.Error())
+		return nil
+	}
+
+	jsIndent, _ := json.MarshalIndent(result, "", "  ")
+	fmt.Println(string(jsIndent))
+
+	return
+}
+
+func Nodes(url string) (err error) {
+
+	rpc := rpcclient.NewJSONRPCClientEx(url, "", true)
+
+	result := new(rpc3.NodesResult)
+	_, err = rpc.Call("bcb_nodes", withChain(map[string]interface{}{}), result)
+	if err != nil {
+		fmt.Printf("Cannot get nodes, error=%s \n", err.Error())
+		return nil
+	}
+
+	jsIndent, _ := json.MarshalIndent(result, "", "  ")
+	fmt.Println(string(jsIndent))
+
+	return
+}
+
+func MetaCacheStats(url string) (err error) {
+
+	rpc := rpcclient.NewJSONRPCClientEx(url, "", true)
+
+	result := new(rpc3.MetaCacheStatsResult)
+	_, err = rpc.Call("bcb_metaCacheStats", withChain(map[string]interface{}{}), result)
+	if err != nil {
+		fmt.Printf("Cannot get metadata cache stats, error=%s \n", err.Error())
+		return nil
+	}
+
+	jsIndent, _ := json.MarshalIndent(result, "", "  ")
+	fmt.Println(string(jsIndent))
+
+	return
+}
+
+func SignedQuery(name, accessKey, key, url string) (err error) {
+
+	rpc := rpcclient.NewJSONRPCClientEx(url, "", true)
+
+	result := new(rpc3.SignedQueryResult)
+	_, err = rpc.Call("bcb_signedQuery", withChain(map[string]interface{}{"name": name, "accessKey": accessKey, "key": key}), result)
+	if err != nil {
+		fmt.Printf("Cannot query with signature, name=%s, key=%s, error=%s \n", name, key, err.Error())
+		return nil
+	}
+
+	jsIndent, _ := json.MarshalIndent(result, "", "  ")
+	fmt.Println(string(jsIndent))
+
+	return
+}
+
+func ConvertUnit(value, amountUnit, url string) (err error) {
+
+	rpc := rpcclient.NewJSONRPCClientEx(url, "", true)
+
+	result := new(rpc3.ConvertUnitResult)
+	_, err = rpc.Call("bcb_convertUnit", withChain(map[string]interface{}{"value": value, "amountUnit": amountUnit}), result)
+	if err != nil {
+		fmt.Printf("Cannot convert unit, value=%s, amountUnit=%s, error=%s \n", value, amountUnit, err.Error())
+		return nil
+	}
+
+	jsIndent, _ := json.MarshalIndent(result, "", "  ")
+	fmt.Println(string(jsIndent))
+
+	return
+}
@@ -0,0 +1,148 @@
+package client
+
+import (
+	rpc3 "bcXwallet/rpc"
+	"blockchain/abciapp_v1.0/keys"
+	"common/rpc/lib/client"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+)
+
+func MultisigCreate(smcAddress, gasLimit, note, to, value, amountUnit, nonce, signers string, threshold int, url string) (err error) {
+
+	rpc := rpcclient.NewJSONRPCClientEx(url, "", true)
+
+	var uNonce uint64
+	if nonce != "" {
+		uNonce, err = strconv.ParseUint(nonce, 10, 64)
+		if err != nil {
+			return
+		}
+	}
+
+	signerList := make([]keys.Address, 0)
+	for _, signer := range strings.Split(signers, ",") {
+		if signer = strings.TrimSpace(signer); signer != "" {
+			signerList = append(signerList, signer)
+		}
+	}
+
+	multisigParam := rpc3.MultisigParam{SmcAddress: smcAddress, GasLimit: gasLimit, Note: note, Nonce: uNonce, To: to, Value: value, AmountUnit: amountUnit, Signers: signerList, Threshold: threshold}
+
+	result := new(rpc3.MultisigTxResult)
+	_, err = rpc.Call("bcb_multisigCreate", withChain(map[string]interface{}{"walletParams": multisigParam}), result)
+	if err != nil {
+		fmt.Printf("Cannot create multi-signature transaction, walletParam=%v,\n error=%s \n", multisigParam, err.Error())
+		return nil
+	}
+
+	jsIndent, _ := json.MarshalIndent(result, "", "  ")
+	fmt.Println(string(jsIndent))
+
+	return
+}
+
+func MultisigSign(name, accessKey, id, url string) (err error) {
+
+	rpc := rpcclient.NewJSONRPCClientEx(url, "", true)
+
+	result := new(rpc3.MultisigTxResult)
+	_, err = rpc.Call("bcb_multisigSign", withChain(map[string]interface{}{"name": name, "accessKey": accessKey, "id": id}), result)
+	if err != nil {
+		fmt.Printf("Cannot sign multi-signature transaction, name=%s, id=%s,\n error=%s \n", name, id, err.Error())
+		return nil
+	}
+
+	jsIndent, _ := json.MarshalIndent(result, "", "  ")
+	fmt.Println(string(jsIndent))
+
+	return
+}
+
+func MultisigSigners(id, url string) (err error) {
+
+	rpc := rpcclient.NewJSONRPCClientEx(url, "", true)
+
+	result := new(rpc3.MultisigSignersResult)
+	_, err = rpc.Call("bcb_multisigSigners", withChain(map[string]interface{}{"id": id}), result)
+	if err != nil {
+		fmt.Printf("Cannot get signers of multi-signature transaction, id=%s, error=%s \n", id, err.Error())
+		return nil
+	}
+
+	jsIndent, _ := json.MarshalIndent(result, "", "  ")
+	fmt.Println(string(jsIndent))
+
+	return
+}
+
+// MultisigExport - export multi-signature transaction, write it to file if file is not empty
+func MultisigExport(id, file, url string) (err error) {
+
+	rpc := rpcclient.NewJSONRPCClientEx(url, "", true)
+
+	result := new(rpc3.MultisigTxResult)
+	_, err = rpc.Call("bcb_multisigExport", withChain(map[string]interface{}{"id": id}), result)
+	if err != nil {
+		fmt.Printf("Cannot export multi-signature transaction, id=%s, error=%s \n", id, err.Error())
+		return nil
+	}
+
+	jsIndent, _ := json.MarshalIndent(result, "", "  ")
+	if file == "" {
+		fmt.Println(string(jsIndent))
+		return
+	}
+
+	if err = ioutil.WriteFile(file, jsIndent, 0600); err != nil {
+		fmt.Printf("Cannot write file %s, error=%s \n", file, err.Error())
+		return nil
+	}
+	fmt.Println("Export to " + file)
+
+	return
+}
+
+// MultisigImport - import multi-signature transaction from file exported by other wallet
+func MultisigImport(file, url string) (err error) {
+
+	rpc := rpcclient.NewJSONRPCClientEx(url, "", true)
+
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		fmt.Printf("Cannot read file %s, error=%s \n", file, err.Error())
+		return nil
+	}
+
+	result := new(rpc3.MultisigTxResult)
+	_, err = rpc.Call("bcb_multisigImport", withChain(map[string]interface{}{"data": string(data)}), result)
+	if err != nil {
+		fmt.Printf("Cannot import multi-signature transaction, file=%s, error=%s \n", file, err.Error())
+		return nil
+	}
+
+	jsIndent, _ := json.MarshalIndent(result, "", "  ")
+	fmt.Println(string(jsIndent))
+
+	return
+}
+
+func MultisigFinalize(id, url string) (err error) {
+
+	rpc := rpcclient.NewJSONRPCClientEx(url, "", true)
+
+	result := new(rpc3.MultisigFinalizeResult)
+	_, err = rpc.Call("bcb_multisigFinalize", withChain(map[string]interface{}{"id": id}), result)
+	if err != nil {
+		fmt.Printf("Cannot finalize multi-signature transaction, id=%s, error=%s \n", id, err.Error())
+		return nil
+	}
+
+	jsIndent, _ := json.MarshalIndent(result, "", "  ")
+	fmt.Println(string(jsIndent))
+
+	return
+}
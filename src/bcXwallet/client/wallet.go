@@ -13,7 +13,7 @@ func WalletCreate(name, password, url string) (err error) {
 	rpc := rpcclient.NewJSONRPCClientEx(url, "", true)
 
 	result := new(rpc3.WalletCreateResult)
-	_, err = rpc.Call("bcb_walletCreate", map[string]interface{}{"name": name, "password": password}, result)
+	_, err = rpc.Call("bcb_walletCreate", withChain(map[string]interface{}{"name": name, "password": password}), result)
 	if err != nil {
 		fmt.Printf("Cannot create wallet, name=%s, password=%s,\n error=%s \n", name, password, err.Error())
 		return nil
@@ -35,7 +35,7 @@ func WalletExport(name, password, accessKey, url, plainText string) (err error)
 	}
 
 	result := new(rpc3.WalletExportResult)
-	_, err = rpc.Call("bcb_walletExport", map[string]interface{}{"name": name, "password": password, "accessKey": accessKey, "plainText": bPlainText}, result)
+	_, err = rpc.Call("bcb_walletExport", withChain(map[string]interface{}{"name": name, "password": password, "accessKey": accessKey, "plainText": bPlainText}), result)
 	if err != nil {
 		fmt.Printf("Cannot export wallet, name=%s, password=%s, accessKey=%s, plainText=%v,\n error=%s \n", name, password, accessKey, plainText, err.Error())
 		return nil
@@ -61,7 +61,7 @@ func WalletImport(name, privateKey, password, accessKey, url, plainText string)
 	}
 
 	result := new(rpc3.WalletImportResult)
-	_, err = rpc.Call("bcb_walletImport", map[string]interface{}{"name": name, "privateKey": privateKey, "password": password, "accessKey": accessKey, "plainText": bPlainText}, result)
+	_, err = rpc.Call("bcb_walletImport", withChain(map[string]interface{}{"name": name, "privateKey": privateKey, "password": password, "accessKey": accessKey, "plainText": bPlainText}), result)
 	if err != nil {
 		fmt.Printf("Cannot import wallet, name=%s, privateKey=%s, password=%s, accessKey=%s, plainText=%v,\n error=%s \n", name, privateKey, password, accessKey, plainText, err.Error())
 		return nil
@@ -78,7 +78,7 @@ func WalletList(pageNum uint64, url string) (err error) {
 	rpc := rpcclient.NewJSONRPCClientEx(url, "", true)
 
 	result := new(rpc3.WalletListResult)
-	_, err = rpc.Call("bcb_walletList", map[string]interface{}{"pageNum": pageNum}, result)
+	_, err = rpc.Call("bcb_walletList", withChain(map[string]interface{}{"pageNum": pageNum}), result)
 	if err != nil {
 		fmt.Printf("Cannot list wallet, error=%s \n", err.Error())
 		return nil
@@ -90,14 +90,14 @@ func WalletList(pageNum uint64, url string) (err error) {
 	return
 }
 
-func Transfer(name, accessKey, smcAddress, gasLimit, note, to, value, url string) (err error) {
+func Transfer(name, accessKey, smcAddress, gasLimit, note, to, value, amountUnit, requestID, url string) (err error) {
 
 	rpc := rpcclient.NewJSONRPCClientEx(url, "", true)
 
-	transferParam := rpc3.TransferParam{SmcAddress: smcAddress, GasLimit: gasLimit, Note: note, To: to, Value: value}
+	transferParam := rpc3.TransferParam{SmcAddress: smcAddress, GasLimit: gasLimit, Note: note, To: to, Value: value, AmountUnit: amountUnit}
 
 	result := new(rpc3.TransferResult)
-	_, err = rpc.Call("bcb_transfer", map[string]interface{}{"name": name, "accessKey": accessKey, "walletParams": transferParam}, result)
+	_, err = rpc.Call("bcb_transfer", withChain(map[string]interface{}{"name": name, "accessKey": accessKey, "walletParams": transferParam, "requestId": requestID}), result)
 	if err != nil {
 		fmt.Printf("Cannot transfer, name=%s, accessKey=%s, walletParam=%v,\n error=%s \n", name, accessKey, transferParam, err.Error())
 		return nil
@@ -109,7 +109,7 @@ func Transfer(name, accessKey, smcAddress, gasLimit, note, to, value, url string
 	return
 }
 
-func TransferOffline(name, accessKey, smcAddress, gasLimit, note, to, value, nonce, url string) (err error) {
+func TransferOffline(name, accessKey, smcAddress, gasLimit, note, to, value, amountUnit, nonce, url string) (err error) {
 
 	rpc := rpcclient.NewJSONRPCClientEx(url, "", true)
 
@@ -117,10 +117,10 @@ func TransferOffline(name, accessKey, smcAddress, gasLimit, note, to, value, non
 	if err != nil {
 		return
 	}
-	transferParam := rpc3.TransferOfflineParam{SmcAddress: smcAddress, GasLimit: gasLimit, Note: note, Nonce: uNonce, To: to, Value: value}
+	transferParam := rpc3.TransferOfflineParam{SmcAddress: smcAddress, GasLimit: gasLimit, Note: note, Nonce: uNonce, To: to, Value: value, AmountUnit: amountUnit}
 
 	result := new(rpc3.TransferOfflineResult)
-	_, err = rpc.Call("bcb_transferOffline", map[string]interface{}{"name": name, "accessKey": accessKey, "walletParams": transferParam}, result)
+	_, err = rpc.Call("bcb_transferOffline", withChain(map[string]interface{}{"name": name, "accessKey": accessKey, "walletParams": transferParam}), result)
 	if err != nil {
 		fmt.Printf("Cannot transferOffline, name=%s, accessKey=%s, walletParam=%v,\n error=%s \n", name, accessKey, transferParam, err.Error())
 		return nil
@@ -0,0 +1,144 @@
+package common
+
+import (
+	"bcXwallet/common/config"
+	"errors"
+	"sync"
+)
+
+// Chain - one chain served by the wallet, requests are sent to its nodes, transactions and addresses
+// are bound to its chain ID, and its wallets are kept in its keystore namespace
+type Chain struct {
+	Name              string
+	ChainID           string
+	NodeAddrSlice     []string
+	KeyStoreNamespace string
+	QueryWallet       string
+	QueryAccessKey    string
+
+	version *chainVersionTracker
+
+	signerMtx sync.RWMutex
+	signer    QuerySigner
+}
+
+var (
+	chainsMtx    sync.Mutex
+	chains       map[string]*Chain
+	chainList    []*Chain
+	defaultChain string
+)
+
+// NewChain - make chain of profile, chainVersion of profile is used until the version of nodes is known
+func NewChain(profile config.ChainProfile) *Chain {
+	return &Chain{
+		Name:              profile.Name,
+		ChainID:           profile.ChainID,
+		NodeAddrSlice:     profile.NodeAddrSlice,
+		KeyStoreNamespace: profile.KeyStoreNamespace,
+		QueryWallet:       profile.QueryWallet,
+		QueryAccessKey:    profile.QueryAccessKey,
+		version:           newChainVersionTracker(profile.NodeAddrSlice, profile.ChainVersion),
+	}
+}
+
+// loadChains - make chains of config once, the caller must hold chainsMtx
+func loadChains() error {
+	if chains != nil {
+		return nil
+	}
+
+	cfg := GetConfig()
+	profiles, err := cfg.Profiles()
+	if err != nil {
+		return err
+	}
+
+	chains = make(map[string]*Chain)
+	chainList = make([]*Chain, 0, len(profiles))
+	for _, profile := range profiles {
+		c := NewChain(profile)
+		chains[c.Name] = c
+		chainList = append(chainList, c)
+	}
+	defaultChain = cfg.DefaultChainName()
+
+	return nil
+}
+
+// GetChain - chain of name in config, empty name means the default chain
+func GetChain(name string) (*Chain, error) {
+	chainsMtx.Lock()
+	defer chainsMtx.Unlock()
+
+	if err := loadChains(); err != nil {
+		return nil, err
+	}
+
+	if name == "" {
+		name = defaultChain
+	}
+	c, ok := chains[name]
+	if !ok {
+		if name == "" {
+			return nil, errors.New("No chain is configured ")
+		}
+		return nil, errors.New("Unknown chain " + name + " ")
+	}
+
+	return c, nil
+}
+
+// DefaultChain - chain of requests without chain, background jobs work on it too
+func DefaultChain() (*Chain, error) {
+	return GetChain("")
+}
+
+// Chains - all chains in the order of config
+func Chains() []*Chain {
+	chainsMtx.Lock()
+	defer chainsMtx.Unlock()
+
+	if err := loadChains(); err != nil {
+		return nil
+	}
+
+	return chainList
+}
+
+// ChainVersion - chain version "1" or "2" that transactions of chain should be encoded with now
+func (c *Chain) ChainVersion() (string, error) {
+	return c.version.get()
+}
+
+// RefreshChainVersion - query chain version of nodes of chain now
+func (c *Chain) RefreshChainVersion() (ChainVersionStatus, error) {
+	err := c.version.refresh()
+
+	return c.version.status(), err
+}
+
+// ObserveChainVersion - chain version in header of block at height, nil or 0 means version 1
+func (c *Chain) ObserveChainVersion(height int64, version *int64) {
+	v := int64(1)
+	if version != nil && *version != 0 {
+		v = *version
+	}
+
+	c.version.raise(v, chainVersionFromBlock, height)
+}
+
+// SetQuerySigner - set signer of queries to nodes of chain, nil means plain unsigned query
+func (c *Chain) SetQuerySigner(signer QuerySigner) {
+	c.signerMtx.Lock()
+	defer c.signerMtx.Unlock()
+
+	c.signer = signer
+}
+
+func (c *Chain) querySigner() QuerySigner {
+	c.signerMtx.RLock()
+	defer c.signerMtx.RUnlock()
+
+	return c.signer
+}
@@ -0,0 +1,64 @@
+package common
+
+import (
+	"bcXwallet/common/config"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProfiles(t *testing.T) {
+	assert := assert.New(t)
+
+	cfg := config.Config{ChainID: "bcb", ChainVersion: "2", NodeAddrSlice: []string{"http://127.0.0.1:37827"}}
+	cfg.Chains = []config.ChainProfile{
+		{Name: "testnet", ChainID: "devtest"},
+		{Name: "side", ChainID: "side", KeyStoreNamespace: "sidechains"},
+	}
+	profiles, err := cfg.Profiles()
+	assert.Nil(err)
+	assert.Equal(3, len(profiles))
+	assert.Equal("bcb", profiles[0].Name)
+	assert.Equal("", profiles[0].KeyStoreNamespace)
+	assert.Equal("2", profiles[0].ChainVersion)
+	assert.Equal("testnet", profiles[1].KeyStoreNamespace)
+	assert.Equal("sidechains", profiles[2].KeyStoreNamespace)
+	assert.Equal("bcb", cfg.DefaultChainName())
+
+	cfg.DefaultChain = "side"
+	_, err = cfg.Profiles()
+	assert.Nil(err)
+	assert.Equal("side", cfg.DefaultChainName())
+
+	cfg.DefaultChain = "unknown"
+	_, err = cfg.Profiles()
+	assert.NotNil(err)
+	cfg.DefaultChain = ""
+
+	// names are unique and a namespace keeps wallets of one chain ID
+	cfg.Chains = append(cfg.Chains, config.ChainProfile{Name: "bcb", ChainID: "bcb"})
+	_, err = cfg.Profiles()
+	assert.NotNil(err)
+	cfg.Chains[2] = config.ChainProfile{Name: "side2", ChainID: "side2", KeyStoreNamespace: "sidechains"}
+	_, err = cfg.Profiles()
+	assert.NotNil(err)
+	cfg.Chains[2] = config.ChainProfile{Name: "side2", ChainID: "side", KeyStoreNamespace: "sidechains"}
+	_, err = cfg.Profiles()
+	assert.Nil(err)
+	cfg.Chains[2] = config.ChainProfile{Name: "side2"}
+	_, err = cfg.Profiles()
+	assert.NotNil(err)
+}
+
+func TestNewChain(t *testing.T) {
+	assert := assert.New(t)
+
+	c := NewChain(config.ChainProfile{Name: "testnet", ChainID: "devtest", ChainVersion: "2", KeyStoreNamespace: "testnet"})
+	assert.Equal("testnet", c.Name)
+	assert.Equal("devtest", c.ChainID)
+	assert.Equal("testnet", c.KeyStoreNamespace)
+
+	version, err := c.ChainVersion()
+	assert.Nil(err)
+	assert.Equal("2", version)
+}
@@ -2,26 +2,37 @@ package common
 
 import (
 	types2 "blockchain/abciapp_v1.0/types"
-	rpcclient "common/rpc/lib/client"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"net/url"
 	"strings"
+
+	abci "github.com/tendermint/abci/types"
 )
 
+// NodeErrorHandler - handle the error of node that cannot be reached
+type NodeErrorHandler func(nodeAddr string, err error)
+
+var nodeErrorHandler NodeErrorHandler
+
+// SetNodeErrorHandler - set handler called when request to node fails in transport
+func SetNodeErrorHandler(handler NodeErrorHandler) {
+	nodeErrorHandler = handler
+}
+
+// reportNodeError - only errors of transport mean the node is unreachable, error responses are not reported
+func reportNodeError(nodeAddr string, err error) {
+	if _, ok := err.(*url.Error); ok && nodeErrorHandler != nil {
+		nodeErrorHandler(nodeAddr, err)
+	}
+}
+
 //网络请求和结果解析
 func DoHttpRequestAndParseEx(nodeAddrSlice []string, methodName string, params map[string]interface{}, result interface{}) (err error) {
 
-	for i, nodeAddr := range nodeAddrSlice {
-		rpc := rpcclient.NewJSONRPCClientEx(nodeAddr, "", true)
-		_, err = rpc.Call(methodName, params, result)
-		if err == nil {
-			break
-		} else {
-			if i == len(nodeAddrSlice)-1 {
-				splitErr := strings.Split(err.Error(), ":")
-				return errors.New(strings.Trim(splitErr[len(splitErr)-1], " "))
-			}
-		}
+	if err = GetNodePool(nodeAddrSlice).Call(methodName, params, result); err != nil {
+		return trimError(err)
 	}
 
 	return
@@ -32,25 +43,37 @@ func DoHttpRequestAndParse(nodeAddrSlice []string, txStr string) (*types2.Result
 
 	result := new(types2.ResultBroadcastTxCommit)
 
-	for i, nodeAddr := range nodeAddrSlice {
-		rpc := rpcclient.NewJSONRPCClientEx(nodeAddr, "", true)
-		_, err := rpc.Call("broadcast_tx_commit", map[string]interface{}{"tx": []byte(txStr)}, result)
-		if err == nil {
-			break
-		} else {
-			if i == len(nodeAddrSlice)-1 {
-				splitErr := strings.Split(err.Error(), ":")
-				return nil, errors.New(strings.Trim(splitErr[len(splitErr)-1], " "))
-			}
-		}
+	if err := GetNodePool(nodeAddrSlice).Call("broadcast_tx_commit", map[string]interface{}{"tx": []byte(txStr)}, result); err != nil {
+		return nil, trimError(err)
 	}
 
 	return result, nil
 }
 
-func DoHttpQueryAndParse(nodeAddrSlice []string, key string, data interface{}) (err error) {
+// ResponseError - error response of node, the node was reached and answered the request with an error
+type ResponseError struct {
+	Message string
+}
 
-	value, err := DoHttpQuery(nodeAddrSlice, key)
+func (e *ResponseError) Error() string {
+	return e.Message
+}
+
+// trimError - keep the last part of error, prefixes of rpc client are removed,
+// error response of node is kept as ResponseError to tell it from errors of transport
+func trimError(err error) error {
+	splitErr := strings.Split(err.Error(), ":")
+	message := strings.Trim(splitErr[len(splitErr)-1], " ")
+	if strings.HasPrefix(err.Error(), "Response error") {
+		return &ResponseError{Message: message}
+	}
+
+	return errors.New(message)
+}
+
+func DoHttpQueryAndParse(c *Chain, key string, data interface{}) (err error) {
+
+	value, err := DoHttpQuery(c, key)
 	if err != nil {
 		return
 	}
@@ -60,22 +83,73 @@ func DoHttpQueryAndParse(nodeAddrSlice []string, key string, data interface{}) (
 	return
 }
 
-func DoHttpQuery(nodeAddrSlice []string, key string) (value []byte, err error) {
+// QuerySigner - sign the query key to <qy> envelope
+type QuerySigner func(key string) (string, error)
 
-	result := new(types2.ResultABCIQuery)
-	for i, nodeAddr := range nodeAddrSlice {
-		rpc := rpcclient.NewJSONRPCClientEx(nodeAddr, "", true)
-		_, err = rpc.Call("abci_query", map[string]interface{}{"path": key}, result)
-		if err == nil {
-			break
-		} else {
-			if i == len(nodeAddrSlice)-1 {
-				splitErr := strings.Split(err.Error(), ":")
-				return nil, errors.New(strings.Trim(splitErr[len(splitErr)-1], " "))
-			}
+func DoHttpQuery(c *Chain, key string) (value []byte, err error) {
+	return DoHttpQueryWithSigner(c, key, c.querySigner())
+}
+
+func DoHttpQueryWithSigner(c *Chain, key string, signer QuerySigner) (value []byte, err error) {
+	return doHttpQuery(c.NodeAddrSlice, key, 0, signer)
+}
+
+// DoHttpQueryAtHeight - query state of key at height of chain, 0 means the latest state
+func DoHttpQueryAtHeight(c *Chain, key string, height int64) (value []byte, err error) {
+	return doHttpQuery(c.NodeAddrSlice, key, height, c.querySigner())
+}
+
+func doHttpQuery(nodeAddrSlice []string, key string, height int64, signer QuerySigner) (value []byte, err error) {
+
+	path := key
+	if signer != nil {
+		if path, err = signer(key); err != nil {
+			return
 		}
 	}
+
+	result := new(types2.ResultABCIQuery)
+	if err = GetNodePool(nodeAddrSlice).Call("abci_query", queryParams(path, height), result); err != nil {
+		return nil, trimError(err)
+	}
+	if err = checkQueryHeight(result.Response, height); err != nil {
+		return
+	}
 	value = result.Response.Value
 
 	return
 }
+
+// HeightError - state at height cannot be served by node, it's pruned or not reached yet
+type HeightError struct {
+	Height int64
+	Reason string
+}
+
+func (e *HeightError) Error() string {
+	return fmt.Sprintf("State at height %d is not available, it may be pruned by node: %s", e.Height, e.Reason)
+}
+
+func queryParams(path string, height int64) map[string]interface{} {
+	params := map[string]interface{}{"path": path}
+	if height > 0 {
+		params["height"] = height
+	}
+
+	return params
+}
+
+// checkQueryHeight - node that cannot serve state at height answers with an error or with the state of other height
+func checkQueryHeight(response types2.ResponseQuery, height int64) error {
+	if height <= 0 {
+		return nil
+	}
+	if response.Code != abci.CodeTypeOK {
+		return &HeightError{Height: height, Reason: response.Log}
+	}
+	if response.Height != height {
+		return &HeightError{Height: height, Reason: fmt.Sprintf("node answered with state at height %d", response.Height)}
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package common
+
+import (
+	"bcXwallet/common/config"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDoHttpQueryAtHeight(t *testing.T) {
+	assert := assert.New(t)
+
+	node := testQueryNode("01", 10)
+	defer node.Close()
+
+	value, err := doHttpQuery([]string{node.URL}, "/account/ex/test", 10, nil)
+	assert.Nil(err)
+	assert.Equal([]byte{1}, value)
+
+	value, err = doHttpQuery([]string{node.URL}, "/account/ex/test", 0, nil)
+	assert.Nil(err)
+	assert.Equal([]byte{1}, value)
+
+	// the node answers with the latest state when state at height is pruned
+	_, err = doHttpQuery([]string{node.URL}, "/account/ex/test", 5, nil)
+	if heightErr, ok := err.(*HeightError); assert.True(ok) {
+		assert.Equal(int64(5), heightErr.Height)
+		assert.Contains(heightErr.Error(), "node answered with state at height 10")
+	}
+
+	// the node cannot serve state at height
+	var calls int32
+	failed := testNode(&calls, `{"response":{"code":404,"log":"state is pruned"}}`)
+	defer failed.Close()
+	_, err = doHttpQuery([]string{failed.URL}, "/account/ex/test", 5, nil)
+	if heightErr, ok := err.(*HeightError); assert.True(ok) {
+		assert.Contains(heightErr.Error(), "state is pruned")
+	}
+}
+
+func TestQuerySignerOfChain(t *testing.T) {
+	assert := assert.New(t)
+
+	// the node answers with the path it's queried with
+	node := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Params struct {
+				Path string `json:"path"`
+			} `json:"params"`
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+		value := base64.StdEncoding.EncodeToString([]byte(request.Params.Path))
+		w.Write([]byte(`{"jsonrpc":"2.0","id":"jsonrpc-client","result":{"response":{"code":200,"value":"` + value + `"}}}`))
+	}))
+	defer node.Close()
+
+	// chains with the same nodes have their own signers
+	signed := NewChain(config.ChainProfile{Name: "signed", ChainID: "bcb", NodeAddrSlice: []string{node.URL}})
+	plain := NewChain(config.ChainProfile{Name: "plain", ChainID: "bcb", NodeAddrSlice: []string{node.URL}})
+	signed.SetQuerySigner(func(key string) (string, error) { return "<qy>" + key, nil })
+
+	value, err := DoHttpQuery(signed, "/account/ex/test")
+	assert.Nil(err)
+	assert.Equal("<qy>/account/ex/test", string(value))
+	value, err = DoHttpQuery(plain, "/account/ex/test")
+	assert.Nil(err)
+	assert.Equal("/account/ex/test", string(value))
+}
@@ -0,0 +1,166 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	core_types "github.com/tendermint/tendermint/rpc/core/types"
+)
+
+const chainVersionRefreshInterval = 10 * time.Second // interval of querying chain version from health in background
+
+// sources of chain version
+const (
+	chainVersionFromConfig = "config"
+	chainVersionFromHealth = "health"
+	chainVersionFromBlock  = "block"
+)
+
+// ChainVersionStatus - chain version that transactions are encoded with, and where it was seen
+type ChainVersionStatus struct {
+	ChainVersion  string `json:"chainVersion"`
+	Source        string `json:"source"`
+	Height        int64  `json:"height"`
+	RefreshedTime int64  `json:"refreshedTime"`
+	ConfigVersion string `json:"configVersion"`
+}
+
+// chainVersionTracker - chain version of nodes, it's refreshed from health and raised by block headers,
+// version in config is replaced by the answer of health, but version seen from nodes is never lowered
+// since the chain is only upgraded, e.g. by contract upgrade1to2
+type chainVersionTracker struct {
+	mtx           sync.Mutex
+	nodeAddrSlice []string
+	configVersion string
+	version       int64 // 0 means unknown
+	source        string
+	height        int64 // height of block header where version was seen
+	refreshed     time.Time
+	refreshing    *versionRefresh // query of health in flight, callers of refresh share it
+}
+
+// versionRefresh - one query of health, done is closed when err is set
+type versionRefresh struct {
+	done chan struct{}
+	err  error
+}
+
+// newChainVersionTracker - chainVersion in config is used until the version of nodes is known
+func newChainVersionTracker(nodeAddrSlice []string, configVersion string) *chainVersionTracker {
+	t := &chainVersionTracker{nodeAddrSlice: nodeAddrSlice, configVersion: configVersion}
+	if v, err := strconv.ParseInt(configVersion, 10, 64); err == nil && v > 0 {
+		t.version = v
+		t.source = chainVersionFromConfig
+	}
+
+	return t
+}
+
+// CheckChainVersion - check chainVersion of all chains in config and query the version of their nodes,
+// config file is never modified
+func CheckChainVersion() {
+	for _, c := range Chains() {
+		configVersion := c.version.configVersion
+		if configVersion != "1" && configVersion != "2" && configVersion != "" {
+			fmt.Printf("Config file error, please check chainVersion of chain %s!\n", c.Name)
+			continue
+		}
+
+		if err := c.version.refresh(); err != nil {
+			fmt.Printf("Query ChainVersion of chain %s failed, please check!\n", c.Name)
+		}
+		go c.version.refreshLoop()
+	}
+}
+
+// refreshLoop - query chain version of nodes every interval, so requests never wait for health
+func (t *chainVersionTracker) refreshLoop() {
+	for {
+		time.Sleep(chainVersionRefreshInterval)
+		if err := t.refresh(); err != nil && GetLogger() != nil {
+			GetLogger().Error("Cannot refresh chain version", "error", err)
+		}
+	}
+}
+
+// get - the last known chain version, it's never queried from nodes here
+func (t *chainVersionTracker) get() (string, error) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if t.version == 0 {
+		return "", errors.New("Chain version is unknown, please check nodes or chainVersion in config ")
+	}
+
+	return strconv.FormatInt(t.version, 10), nil
+}
+
+// refresh - query health of nodes, callers at the same time wait for the same query instead of querying again
+func (t *chainVersionTracker) refresh() error {
+	t.mtx.Lock()
+	r := t.refreshing
+	if r == nil {
+		r = &versionRefresh{done: make(chan struct{})}
+		t.refreshing = r
+		go func() {
+			r.err = t.queryHealth()
+
+			t.mtx.Lock()
+			t.refreshed = time.Now()
+			t.refreshing = nil
+			t.mtx.Unlock()
+			close(r.done)
+		}()
+	}
+	t.mtx.Unlock()
+
+	<-r.done
+	return r.err
+}
+
+// queryHealth - query health of nodes, chain version 0 of health means version 1
+func (t *chainVersionTracker) queryHealth() error {
+	result := new(core_types.ResultHealth)
+	if err := DoHttpRequestAndParseEx(t.nodeAddrSlice, "health", map[string]interface{}{}, result); err != nil {
+		return err
+	}
+
+	v := result.ChainVersion
+	if v == 0 {
+		v = 1
+	}
+	t.raise(v, chainVersionFromHealth, result.LastBlockHeight)
+
+	return nil
+}
+
+// raise - version seen from nodes, health overrides version in config even if it's lower,
+// block header may be an old block so it only raises the version
+func (t *chainVersionTracker) raise(version int64, source string, height int64) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if version > t.version || (t.source == chainVersionFromConfig && source == chainVersionFromHealth) {
+		t.version = version
+		t.source = source
+		t.height = height
+	}
+}
+
+func (t *chainVersionTracker) status() ChainVersionStatus {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	status := ChainVersionStatus{Source: t.source, Height: t.height, ConfigVersion: t.configVersion}
+	if t.version != 0 {
+		status.ChainVersion = strconv.FormatInt(t.version, 10)
+	}
+	if !t.refreshed.IsZero() {
+		status.RefreshedTime = t.refreshed.Unix()
+	}
+
+	return status
+}
@@ -0,0 +1,120 @@
+package common
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChainVersionTracker(t *testing.T) {
+	assert := assert.New(t)
+
+	var calls int32
+	v1 := testNode(&calls, `{"chain_version":0,"last_block_height":10}`)
+	defer v1.Close()
+	v2 := testNode(&calls, `{"chain_version":2,"last_block_height":20}`)
+	defer v2.Close()
+
+	// get never queries nodes
+	tracker := newChainVersionTracker([]string{v1.URL}, "1")
+	version, err := tracker.get()
+	assert.Nil(err)
+	assert.Equal("1", version)
+	assert.Equal(chainVersionFromConfig, tracker.status().Source)
+	assert.Equal(int32(0), atomic.LoadInt32(&calls))
+	assert.Nil(tracker.refresh())
+	assert.Equal(chainVersionFromHealth, tracker.status().Source)
+	assert.Equal(int64(10), tracker.status().Height)
+
+	// the chain is upgraded, it's seen after refresh
+	tracker.nodeAddrSlice = []string{v2.URL}
+	version, err = tracker.get()
+	assert.Nil(err)
+	assert.Equal("1", version)
+	assert.Nil(tracker.refresh())
+	version, err = tracker.get()
+	assert.Nil(err)
+	assert.Equal("2", version)
+	status := tracker.status()
+	assert.Equal(chainVersionFromHealth, status.Source)
+	assert.Equal(int64(20), status.Height)
+	assert.Equal("1", status.ConfigVersion)
+
+	// version of lagging node or old block never lowers it
+	tracker.nodeAddrSlice = []string{v1.URL}
+	assert.Nil(tracker.refresh())
+	tracker.raise(1, chainVersionFromBlock, 5)
+	version, _ = tracker.get()
+	assert.Equal("2", version)
+
+	// version in config is wrong, it's replaced by health of nodes but not by an old block
+	tracker = newChainVersionTracker([]string{v1.URL}, "2")
+	tracker.raise(1, chainVersionFromBlock, 5)
+	assert.Equal(chainVersionFromConfig, tracker.status().Source)
+	assert.Nil(tracker.refresh())
+	version, err = tracker.get()
+	assert.Nil(err)
+	assert.Equal("1", version)
+	assert.Equal(chainVersionFromHealth, tracker.status().Source)
+
+	// nothing is known without config and nodes
+	dead := httptest.NewServer(nil)
+	dead.Close()
+	tracker = newChainVersionTracker([]string{dead.URL}, "")
+	assert.NotNil(tracker.refresh())
+	_, err = tracker.get()
+	assert.NotNil(err)
+	tracker.raise(2, chainVersionFromBlock, 30)
+	version, err = tracker.get()
+	assert.Nil(err)
+	assert.Equal("2", version)
+	assert.Equal(int64(30), tracker.status().Height)
+
+	// version in config is used while nodes are unreachable
+	tracker = newChainVersionTracker([]string{dead.URL}, "2")
+	assert.NotNil(tracker.refresh())
+	version, err = tracker.get()
+	assert.Nil(err)
+	assert.Equal("2", version)
+	assert.Equal(chainVersionFromConfig, tracker.status().Source)
+}
+
+func TestChainVersionRefreshOnce(t *testing.T) {
+	assert := assert.New(t)
+
+	var calls int32
+	node := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// health checks of node pool query status
+		if body, _ := ioutil.ReadAll(r.Body); strings.Contains(string(body), `"health"`) {
+			atomic.AddInt32(&calls, 1)
+		}
+		time.Sleep(50 * time.Millisecond)
+		w.Write([]byte(`{"jsonrpc":"2.0","id":"jsonrpc-client","result":{"chain_version":2,"last_block_height":20}}`))
+	}))
+	defer node.Close()
+
+	// concurrent refreshes share one query of health
+	tracker := newChainVersionTracker([]string{node.URL}, "")
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Nil(tracker.refresh())
+		}()
+	}
+	wg.Wait()
+	assert.Equal(int32(1), atomic.LoadInt32(&calls))
+
+	version, err := tracker.get()
+	assert.Nil(err)
+	assert.Equal("2", version)
+	assert.Equal(int32(1), atomic.LoadInt32(&calls))
+}
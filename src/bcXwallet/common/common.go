@@ -3,16 +3,10 @@ package common
 import (
 	"bcXwallet/common/config"
 	"blockchain/tx2"
-	"bufio"
-	"fmt"
-	"io"
 	"os"
-	"strconv"
-	"strings"
 
 	"github.com/pkg/errors"
 	"github.com/tendermint/go-crypto"
-	core_types "github.com/tendermint/tendermint/rpc/core/types"
 	"github.com/tendermint/tmlibs/log"
 )
 
@@ -31,11 +25,14 @@ func InitAll() error {
 	}
 	initLog(moduleName)
 
-	if bcbXWalletConfig.ChainID == "" {
+	c, err := DefaultChain()
+	if err != nil {
 		return errors.New(" chainId cannot be empty")
 	}
-	crypto.SetChainId(bcbXWalletConfig.ChainID)
-	tx2.Init(bcbXWalletConfig.ChainID)
+	// wallet signs and derives addresses with chain ID of every chain, the global one is only the default
+	// of libraries
+	crypto.SetChainId(c.ChainID)
+	tx2.Init(c.ChainID)
 
 	CheckChainVersion()
 
@@ -90,84 +87,3 @@ func OutCertFileIsExist() (string, string) {
 
 	return bcbXWalletConfig.OutCertPath + ".crt", bcbXWalletConfig.OutCertPath + ".key"
 }
-
-func CheckChainVersion() {
-	cfg := bcbXWalletConfig
-
-	if cfg.ChainVersion != "1" && cfg.ChainVersion != "2" && cfg.ChainVersion != "" {
-		fmt.Println("Config file error, please check chainVersion!")
-		return
-	}
-
-	if cfg.ChainVersion == "2" {
-		return
-	}
-
-	ChainVersion, err := queryChainVersion()
-	if err != nil {
-		fmt.Println("Query ChainVersion failed, please check!")
-		return
-	}
-
-	if ChainVersion == "0" {
-		ChainVersion = "1"
-	}
-
-	if cfg.ChainVersion != ChainVersion {
-		changeChainVersion(ChainVersion)
-		bcbXWalletConfig.ChainVersion = ChainVersion
-	}
-}
-
-func queryChainVersion() (chainVersion string, err error) {
-	result := new(core_types.ResultHealth)
-	params := map[string]interface{}{}
-	err = DoHttpRequestAndParseEx(GetConfig().NodeAddrSlice, "health", params, result)
-	if err != nil {
-		return "", err
-	}
-
-	chainVersion = strconv.FormatInt(result.ChainVersion, 10)
-	return
-}
-
-func changeChainVersion(chainversion string) {
-	configFile := "./.config/bcbXwallet.yaml"
-
-	f, err := os.Open(configFile)
-	if err != nil {
-		fmt.Println("OpenFile failed, please check!")
-		return
-	}
-	defer f.Close()
-
-	buf := bufio.NewReader(f)
-	var Str string
-	for {
-		line, err := buf.ReadString('\n')
-		if strings.HasPrefix(line, "chainVersion:") {
-			line = "chainVersion: " + chainversion + "\n"
-		}
-		Str = Str + line
-
-		if err != nil {
-			if err == io.EOF {
-				break
-			}
-			return
-		}
-	}
-
-	file2, err := os.Create(configFile)
-	if err != nil {
-		fmt.Println("CreateFile failed, please check!")
-		return
-	}
-	defer file2.Close()
-
-	_, err = file2.WriteString(Str)
-	if err != nil {
-		fmt.Println("WriteFile failed, please check!")
-		return
-	}
-}
@@ -2,6 +2,7 @@ package config
 
 import (
 	rpcclient "common/rpc/lib/client"
+	"errors"
 	"fmt"
 	"github.com/tendermint/tendermint/rpc/core/types"
 	"gopkg.in/yaml.v2"
@@ -18,11 +19,115 @@ type Config struct {
 	KeyStorePath  string   `yaml:"keyStorePath"`
 	ChainVersion  string   `yaml:"chainVersion"`
 
+	Chains       []ChainProfile `yaml:"chains"`
+	DefaultChain string         `yaml:"defaultChain"`
+
+	QueryWallet        string `yaml:"queryWallet"`
+	QueryAccessKey     string `yaml:"queryAccessKey"`
+	QueryAccessKeyFile string `yaml:"queryAccessKeyFile"`
+
+	IndexerEnabled     bool  `yaml:"indexerEnabled"`
+	IndexerStartHeight int64 `yaml:"indexerStartHeight"`
+
+	DepositEnabled       bool     `yaml:"depositEnabled"`
+	DepositStartHeight   int64    `yaml:"depositStartHeight"`
+	DepositConfirmations int64    `yaml:"depositConfirmations"`
+	DepositWatchList     []string `yaml:"depositWatchList"`
+
+	ReconcileEnabled  bool  `yaml:"reconcileEnabled"`
+	ReconcileInterval int64 `yaml:"reconcileInterval"`
+
+	WebhookURLs        []string `yaml:"webhookUrls"`
+	WebhookSecret      string   `yaml:"webhookSecret"`
+	WebhookMaxAttempts int      `yaml:"webhookMaxAttempts"`
+
+	MetaCacheSize int `yaml:"metaCacheSize"`
+
+	QuorumSize   int   `yaml:"quorumSize"`
+	QuorumMaxLag int64 `yaml:"quorumMaxLag"`
+
 	LoggerScreen bool   `yaml:"loggerScreen"`
 	LoggerFile   bool   `yaml:"loggerFile"`
 	LoggerLevel  string `yaml:"loggerLevel"`
 }
 
+// ChainProfile - nodes, chain ID, version and keystore namespace of one chain served by the wallet,
+// wallets of chains with the same keystore namespace are shared, so they must have the same chain ID
+type ChainProfile struct {
+	Name              string   `yaml:"name"`
+	ChainID           string   `yaml:"chainID"`
+	ChainVersion      string   `yaml:"chainVersion"`
+	NodeAddrSlice     []string `yaml:"nodeAddrSlice"`
+	KeyStoreNamespace string   `yaml:"keyStoreNamespace"`
+
+	QueryWallet        string `yaml:"queryWallet"`
+	QueryAccessKey     string `yaml:"queryAccessKey"`
+	QueryAccessKeyFile string `yaml:"queryAccessKeyFile"`
+}
+
+// Profiles - chain of top-level settings named by its chain ID with the legacy keystore namespace "",
+// followed by chains in config, namespace of them is their name if it's not set
+func (c *Config) Profiles() ([]ChainProfile, error) {
+	profiles := make([]ChainProfile, 0, len(c.Chains)+1)
+	if c.ChainID != "" {
+		profiles = append(profiles, ChainProfile{
+			Name:           c.ChainID,
+			ChainID:        c.ChainID,
+			ChainVersion:   c.ChainVersion,
+			NodeAddrSlice:  c.NodeAddrSlice,
+			QueryWallet:    c.QueryWallet,
+			QueryAccessKey: c.QueryAccessKey,
+		})
+	}
+
+	names := make(map[string]bool)
+	namespaces := make(map[string]string)
+	if len(profiles) > 0 {
+		names[c.ChainID] = true
+		namespaces[""] = c.ChainID
+	}
+	for _, profile := range c.Chains {
+		if profile.Name == "" || profile.ChainID == "" {
+			return nil, errors.New("name and chainID of chain cannot be empty")
+		}
+		if names[profile.Name] {
+			return nil, fmt.Errorf("duplicate chain %s", profile.Name)
+		}
+		names[profile.Name] = true
+
+		if profile.KeyStoreNamespace == "" {
+			profile.KeyStoreNamespace = profile.Name
+		}
+		if chainID, ok := namespaces[profile.KeyStoreNamespace]; ok && chainID != profile.ChainID {
+			return nil, fmt.Errorf("keyStoreNamespace %s of chain %s is used by chain ID %s", profile.KeyStoreNamespace, profile.Name, chainID)
+		}
+		namespaces[profile.KeyStoreNamespace] = profile.ChainID
+
+		profiles = append(profiles, profile)
+	}
+
+	if c.DefaultChain != "" && !names[c.DefaultChain] {
+		return nil, fmt.Errorf("defaultChain %s is not configured", c.DefaultChain)
+	}
+
+	return profiles, nil
+}
+
+// DefaultChainName - chain of requests without chain, it's the top-level chain if defaultChain is not set
+func (c *Config) DefaultChainName() string {
+	if c.DefaultChain != "" {
+		return c.DefaultChain
+	}
+	if c.ChainID != "" {
+		return c.ChainID
+	}
+	if len(c.Chains) > 0 {
+		return c.Chains[0].Name
+	}
+
+	return ""
+}
+
 func (c *Config) InitConfig(configFile string) error {
 	yamlFile, err := ioutil.ReadFile(configFile)
 	if err != nil {
@@ -42,34 +147,70 @@ func (c *Config) InitConfig(configFile string) error {
 		c.KeyStorePath = "./.keystore"
 	}
 
+	for index := range c.Chains {
+		if len(c.Chains[index].NodeAddrSlice) == 0 {
+			c.Chains[index].NodeAddrSlice = []string{"http://127.0.0.1:37827"}
+		}
+	}
+
+	if err = readAccessKey(&c.QueryAccessKey, c.QueryAccessKeyFile); err != nil {
+		fmt.Printf("QueryAccessKeyFile: %v\n", err)
+		return err
+	}
+	for index := range c.Chains {
+		if err = readAccessKey(&c.Chains[index].QueryAccessKey, c.Chains[index].QueryAccessKeyFile); err != nil {
+			fmt.Printf("QueryAccessKeyFile: %v\n", err)
+			return err
+		}
+	}
+
+	if _, err = c.Profiles(); err != nil {
+		fmt.Printf("Chains: %v\n", err)
+		return err
+	}
+
 	return c.initProtocol()
 }
 
+// readAccessKey - access key is read from file if the file is set, so it's not kept in config file
+func readAccessKey(accessKey *string, file string) error {
+	if file == "" {
+		return nil
+	}
+
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	*accessKey = strings.TrimSpace(string(data))
+
+	return nil
+}
+
+// initProtocol - add https or http to addresses without scheme, unreachable nodes are kept
+// and their scheme is resolved by health checks of node pool when they recover
 func (c *Config) initProtocol() error {
-	result := new(core_types.ResultABCIInfo)
-	for index, ip := range c.NodeAddrSlice {
-		if !strings.HasPrefix(ip, "http") {
-			httpsIp := "https://" + ip
-
-			rpc := rpcclient.NewJSONRPCClientEx(httpsIp, "", true)
-			_, err := rpc.Call("abci_info", map[string]interface{}{}, result)
-			if err == nil {
-				c.NodeAddrSlice[index] = httpsIp
-				return nil
-			}
+	initNodeProtocol(c.NodeAddrSlice)
+	for _, profile := range c.Chains {
+		initNodeProtocol(profile.NodeAddrSlice)
+	}
 
-			httpIp := "http://" + ip
+	return nil
+}
 
-			rpc = rpcclient.NewJSONRPCClientEx(httpIp, "", true)
-			_, err = rpc.Call("abci_info", map[string]interface{}{}, result)
-			if err == nil {
-				c.NodeAddrSlice[index] = httpIp
-				return nil
-			} else {
-				c.NodeAddrSlice = append(c.NodeAddrSlice[:index], c.NodeAddrSlice[index+1:]...)
+func initNodeProtocol(nodeAddrSlice []string) {
+	result := new(core_types.ResultABCIInfo)
+	for index, ip := range nodeAddrSlice {
+		if strings.HasPrefix(ip, "http") {
+			continue
+		}
+
+		for _, addr := range []string{"https://" + ip, "http://" + ip} {
+			rpc := rpcclient.NewJSONRPCClientEx(addr, "", true)
+			if _, err := rpc.Call("abci_info", map[string]interface{}{}, result); err == nil {
+				nodeAddrSlice[index] = addr
+				break
 			}
 		}
 	}
-
-	return nil
 }
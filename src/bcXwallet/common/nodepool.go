@@ -0,0 +1,300 @@
+package common
+
+import (
+	rpcclient "common/rpc/lib/client"
+	"errors"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	core_types "github.com/tendermint/tendermint/rpc/core/types"
+)
+
+const (
+	healthCheckInterval = 5 * time.Second
+	healthCheckTimeout  = 5 * time.Second  // timeout of status request of health check
+	nodeCallTimeout     = 30 * time.Second // timeout of request to node, broadcast_tx_commit waits for the block
+	nodeDialTimeout     = 3 * time.Second  // timeout of connecting to node
+	breakerFailures     = 3                // consecutive transport failures that open the circuit of node
+	breakerCooldown     = 30 * time.Second // wait time before an open circuit is tried again
+	latencyWeight       = 0.3              // weight of the newest sample in moving average of latency
+)
+
+// states of node's circuit breaker
+const (
+	circuitClosed   = "closed"
+	circuitOpen     = "open"
+	circuitHalfOpen = "halfOpen"
+)
+
+// NodeStatus - state of node in pool
+type NodeStatus struct {
+	Addr        string `json:"addr"`
+	URL         string `json:"url"`
+	Healthy     bool   `json:"healthy"`
+	CatchingUp  bool   `json:"catchingUp"`
+	Height      int64  `json:"height"`
+	LatencyMs   int64  `json:"latencyMs"`
+	Failures    int    `json:"failures"`
+	Circuit     string `json:"circuit"`
+	Divergences uint64 `json:"divergences"`
+	LastError   string `json:"lastError,omitempty"`
+	LastCheckAt int64  `json:"lastCheckAt"`
+}
+
+// poolNode - node with its reused clients, url is empty until the scheme of address without it is resolved
+type poolNode struct {
+	addr        string
+	url         string
+	client      *rpcclient.JSONRPCClient
+	checkClient *rpcclient.JSONRPCClient // client of health check with shorter timeout
+	healthy     bool
+	catchingUp  bool
+	height      int64
+	latency     time.Duration
+	failures    int
+	divergences uint64 // count of quorum reads that the node's answer differs from others
+	openUntil   time.Time
+	lastError   string
+	lastCheck   time.Time
+}
+
+// NodePool - nodes of one chain with health checks, latency-aware selection and circuit breaker
+type NodePool struct {
+	mtx               sync.RWMutex
+	nodes             []*poolNode
+	quorumReads       uint64
+	quorumDivergences uint64
+}
+
+var (
+	poolsMtx sync.Mutex
+	pools    = make(map[string]*NodePool)
+)
+
+// GetNodePool - pool of nodes, the same nodes share one pool and its health checks
+func GetNodePool(nodeAddrSlice []string) *NodePool {
+	key := strings.Join(nodeAddrSlice, ",")
+
+	poolsMtx.Lock()
+	defer poolsMtx.Unlock()
+
+	pool, ok := pools[key]
+	if !ok {
+		pool = NewNodePool(nodeAddrSlice)
+		pools[key] = pool
+		go pool.checkLoop()
+	}
+
+	return pool
+}
+
+// NewNodePool - make pool of nodes, all nodes are considered healthy before they are checked
+func NewNodePool(nodeAddrSlice []string) *NodePool {
+	pool := new(NodePool)
+	for _, addr := range nodeAddrSlice {
+		node := &poolNode{addr: addr, healthy: true}
+		if strings.HasPrefix(addr, "http") {
+			node.url = addr
+			node.client = newNodeClient(addr, nodeCallTimeout)
+			node.checkClient = newNodeClient(addr, healthCheckTimeout)
+		}
+		pool.nodes = append(pool.nodes, node)
+	}
+
+	return pool
+}
+
+// newNodeClient - client of node whose requests never wait longer than timeout
+func newNodeClient(nodeURL string, timeout time.Duration) *rpcclient.JSONRPCClient {
+	client := rpcclient.NewJSONRPCClientEx(nodeURL, "", false)
+	client.SetTimeout(timeout, nodeDialTimeout)
+
+	return client
+}
+
+// Call - call method on the best available node, fail over to the next one only if the node cannot be reached
+func (p *NodePool) Call(method string, params map[string]interface{}, result interface{}) (err error) {
+
+	err = errors.New("no node is available")
+	for _, node := range p.candidates(time.Now()) {
+		p.mtx.RLock()
+		nodeURL, client := node.url, node.client
+		p.mtx.RUnlock()
+
+		start := time.Now()
+		_, err = client.Call(method, params, result)
+		if _, ok := err.(*url.Error); ok {
+			p.recordFailure(node, err)
+			reportNodeError(nodeURL, err)
+			continue
+		}
+
+		// the node answered, an error response is returned as it is
+		p.recordSuccess(node, time.Since(start))
+		return
+	}
+
+	return
+}
+
+// candidates - nodes to try in order, healthy nodes with closed circuit by latency first,
+// then half-open and unhealthy nodes, nodes with open circuit are skipped unless no other node exists
+func (p *NodePool) candidates(now time.Time) []*poolNode {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+
+	var preferred, others, open []*poolNode
+	for _, node := range p.nodes {
+		switch {
+		case node.client == nil:
+			continue
+		case node.circuit(now) == circuitOpen:
+			open = append(open, node)
+		case node.circuit(now) == circuitClosed && node.healthy && !node.catchingUp:
+			preferred = append(preferred, node)
+		default:
+			others = append(others, node)
+		}
+	}
+
+	sort.SliceStable(preferred, func(i, j int) bool { return preferred[i].latency < preferred[j].latency })
+
+	result := append(preferred, others...)
+	if len(result) == 0 {
+		result = open
+	}
+
+	return result
+}
+
+func (node *poolNode) circuit(now time.Time) string {
+	switch {
+	case node.failures < breakerFailures:
+		return circuitClosed
+	case now.Before(node.openUntil):
+		return circuitOpen
+	default:
+		return circuitHalfOpen
+	}
+}
+
+func (p *NodePool) recordSuccess(node *poolNode, latency time.Duration) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	if node.latency == 0 {
+		node.latency = latency
+	} else {
+		node.latency = time.Duration(latencyWeight*float64(latency) + (1-latencyWeight)*float64(node.latency))
+	}
+	node.failures = 0
+	node.healthy = true
+	node.lastError = ""
+}
+
+func (p *NodePool) recordFailure(node *poolNode, err error) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	node.failures++
+	node.healthy = false
+	node.lastError = err.Error()
+	if node.failures >= breakerFailures {
+		node.openUntil = time.Now().Add(breakerCooldown)
+	}
+}
+
+// checkLoop - check every node in its own loop, so a node that doesn't answer never delays checks of others
+func (p *NodePool) checkLoop() {
+	p.mtx.RLock()
+	nodes := make([]*poolNode, len(p.nodes))
+	copy(nodes, p.nodes)
+	p.mtx.RUnlock()
+
+	for _, node := range nodes {
+		go func(node *poolNode) {
+			for {
+				p.check(node)
+				time.Sleep(healthCheckInterval)
+			}
+		}(node)
+	}
+}
+
+// check - check status of node, node that recovers rejoins the pool by this
+func (p *NodePool) check(node *poolNode) {
+	p.mtx.RLock()
+	nodeURL, checkClient := node.url, node.checkClient
+	p.mtx.RUnlock()
+
+	urls := []string{nodeURL}
+	if checkClient == nil {
+		// try https first, then http, the same as initProtocol
+		urls = []string{"https://" + node.addr, "http://" + node.addr}
+	}
+
+	var err error
+	for _, u := range urls {
+		if checkClient == nil || u != nodeURL {
+			checkClient = newNodeClient(u, healthCheckTimeout)
+		}
+
+		result := new(core_types.ResultStatus)
+		start := time.Now()
+		if _, err = checkClient.Call("status", map[string]interface{}{}, result); err != nil {
+			continue
+		}
+
+		p.mtx.Lock()
+		if node.url != u {
+			node.url, node.client = u, newNodeClient(u, nodeCallTimeout)
+		}
+		node.checkClient = checkClient
+		node.catchingUp = result.SyncInfo.Syncing
+		node.height = result.SyncInfo.LatestBlockHeight
+		node.lastCheck = time.Now()
+		p.mtx.Unlock()
+
+		p.recordSuccess(node, time.Since(start))
+		return
+	}
+
+	p.mtx.Lock()
+	node.lastCheck = time.Now()
+	p.mtx.Unlock()
+
+	p.recordFailure(node, err)
+	reportNodeError(node.addr, err)
+}
+
+// Nodes - status of all nodes in pool
+func (p *NodePool) Nodes() []NodeStatus {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+
+	now := time.Now()
+	status := make([]NodeStatus, 0, len(p.nodes))
+	for _, node := range p.nodes {
+		s := NodeStatus{
+			Addr:        node.addr,
+			URL:         node.url,
+			Healthy:     node.healthy,
+			CatchingUp:  node.catchingUp,
+			Height:      node.height,
+			LatencyMs:   int64(node.latency / time.Millisecond),
+			Failures:    node.failures,
+			Divergences: node.divergences,
+			Circuit:     node.circuit(now),
+			LastError:   node.lastError,
+		}
+		if !node.lastCheck.IsZero() {
+			s.LastCheckAt = node.lastCheck.Unix()
+		}
+		status = append(status, s)
+	}
+
+	return status
+}
@@ -0,0 +1,141 @@
+package common
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testNode - JSON-RPC node that answers every call with the same result
+func testNode(calls *int32, result string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ioutil.ReadAll(r.Body)
+		atomic.AddInt32(calls, 1)
+		w.Write([]byte(`{"jsonrpc":"2.0","id":"jsonrpc-client","result":` + result + `}`))
+	}))
+}
+
+func TestNodePoolFailover(t *testing.T) {
+	assert := assert.New(t)
+
+	var calls int32
+	node := testNode(&calls, `{"value":"1"}`)
+	defer node.Close()
+
+	// the first node is dead
+	dead := httptest.NewServer(http.NotFoundHandler())
+	deadURL := dead.URL
+	dead.Close()
+
+	pool := NewNodePool([]string{deadURL, node.URL})
+
+	for i := 0; i < 3; i++ {
+		result := make(map[string]string)
+		assert.Nil(pool.Call("test", map[string]interface{}{}, &result))
+		assert.Equal("1", result["value"])
+	}
+	assert.Equal(int32(3), atomic.LoadInt32(&calls))
+
+	// the dead node is unhealthy after the first failure, it's tried after healthy nodes
+	nodes := pool.Nodes()
+	assert.False(nodes[0].Healthy)
+	assert.Equal(1, nodes[0].Failures)
+	assert.True(nodes[1].Healthy)
+
+	candidates := pool.candidates(time.Now())
+	if assert.Equal(2, len(candidates)) {
+		assert.Equal(node.URL, candidates[0].url)
+		assert.Equal(deadURL, candidates[1].url)
+	}
+
+	// the circuit is open after consecutive failures, the node is skipped until cooldown
+	for i := 1; i < breakerFailures; i++ {
+		pool.recordFailure(pool.nodes[0], errors.New("refused"))
+	}
+	assert.Equal(circuitOpen, pool.Nodes()[0].Circuit)
+
+	candidates = pool.candidates(time.Now())
+	if assert.Equal(1, len(candidates)) {
+		assert.Equal(node.URL, candidates[0].url)
+	}
+
+	candidates = pool.candidates(time.Now().Add(breakerCooldown))
+	if assert.Equal(2, len(candidates)) {
+		assert.Equal(deadURL, candidates[1].url)
+	}
+	assert.Equal(circuitHalfOpen, pool.nodes[0].circuit(time.Now().Add(breakerCooldown)))
+}
+
+func TestNodePoolRecover(t *testing.T) {
+	assert := assert.New(t)
+
+	var calls int32
+	node := testNode(&calls, `{"sync_info":{"latest_block_height":12,"syncing":false}}`)
+	defer node.Close()
+
+	pool := NewNodePool([]string{node.URL})
+	pool.nodes[0].failures = breakerFailures
+	pool.nodes[0].healthy = false
+	pool.nodes[0].openUntil = time.Now().Add(breakerCooldown)
+
+	pool.check(pool.nodes[0])
+
+	nodes := pool.Nodes()
+	assert.True(nodes[0].Healthy)
+	assert.Equal(circuitClosed, nodes[0].Circuit)
+	assert.Equal(int64(12), nodes[0].Height)
+	assert.NotZero(nodes[0].LastCheckAt)
+}
+
+func TestNodePoolCheckLoop(t *testing.T) {
+	assert := assert.New(t)
+
+	// the first node accepts connections but never answers
+	var calls int32
+	block := make(chan struct{})
+	silent := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-block
+	}))
+	defer silent.Close()
+	defer close(block)
+	node := testNode(&calls, `{"sync_info":{"latest_block_height":12,"syncing":false}}`)
+	defer node.Close()
+
+	pool := NewNodePool([]string{silent.URL, node.URL})
+	pool.checkLoop()
+
+	// the answering node is checked without waiting for the silent one
+	deadline := time.Now().Add(healthCheckTimeout / 2)
+	for atomic.LoadInt32(&calls) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	time.Sleep(10 * time.Millisecond)
+	nodes := pool.Nodes()
+	assert.Zero(nodes[0].LastCheckAt)
+	assert.NotZero(nodes[1].LastCheckAt)
+	assert.Equal(int64(12), nodes[1].Height)
+}
+
+func TestNodePoolLatency(t *testing.T) {
+	assert := assert.New(t)
+
+	pool := NewNodePool([]string{"http://127.0.0.1:1", "http://127.0.0.1:2", "http://127.0.0.1:3"})
+	pool.recordSuccess(pool.nodes[0], 30*time.Millisecond)
+	pool.recordSuccess(pool.nodes[1], 10*time.Millisecond)
+	pool.recordSuccess(pool.nodes[2], 20*time.Millisecond)
+	pool.nodes[2].catchingUp = true
+
+	candidates := pool.candidates(time.Now())
+	if assert.Equal(3, len(candidates)) {
+		assert.Equal("http://127.0.0.1:2", candidates[0].url)
+		assert.Equal("http://127.0.0.1:1", candidates[1].url)
+		// catching up node is tried last
+		assert.Equal("http://127.0.0.1:3", candidates[2].url)
+	}
+}
@@ -0,0 +1,320 @@
+package common
+
+import (
+	types2 "blockchain/abciapp_v1.0/types"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	core_types "github.com/tendermint/tendermint/rpc/core/types"
+)
+
+const defaultQuorumMaxLag = 3 // max difference of heights in agreed answers if quorumMaxLag is not set in config
+
+// QuorumAnswer - answer of one node in quorum read
+type QuorumAnswer struct {
+	Node   string `json:"node"`
+	Height int64  `json:"height"`
+	Value  string `json:"value"`
+	Error  string `json:"error,omitempty"`
+}
+
+// DivergenceError - nodes of quorum read returned different answers
+type DivergenceError struct {
+	Target  string
+	Answers []QuorumAnswer
+}
+
+func (e *DivergenceError) Error() string {
+	answers := make([]string, 0, len(e.Answers))
+	for _, a := range e.Answers {
+		value := a.Value
+		if a.Error != "" {
+			value = "error " + a.Error
+		}
+		answers = append(answers, fmt.Sprintf("%s at height %d returned %s", a.Node, a.Height, value))
+	}
+
+	return "Nodes diverge on " + e.Target + ", " + strings.Join(answers, "; ")
+}
+
+// quorumSize - count of nodes to query for critical reads, 0 or 1 means quorum mode is off
+func quorumSize() int {
+	return GetConfig().QuorumSize
+}
+
+func quorumMaxLag() int64 {
+	if lag := GetConfig().QuorumMaxLag; lag > 0 {
+		return lag
+	}
+
+	return defaultQuorumMaxLag
+}
+
+// DoQuorumQuery - query state of key at height from quorumSize nodes if quorum mode is on, 0 means the latest state,
+// which is read at the common height of the nodes in quorum mode
+func DoQuorumQuery(c *Chain, key string, height int64) (value []byte, err error) {
+	k := quorumSize()
+	if k <= 1 {
+		return DoHttpQueryAtHeight(c, key, height)
+	}
+
+	path := key
+	if signer := c.querySigner(); signer != nil {
+		if path, err = signer(key); err != nil {
+			return
+		}
+	}
+
+	// nodes may lag each other at the latest state, read all of them at the highest block they all have
+	pool := GetNodePool(c.NodeAddrSlice)
+	if height <= 0 {
+		if height, err = pool.CommonHeight(k); err != nil {
+			return
+		}
+	}
+
+	result, err := pool.QuorumCall(k, quorumMaxLag(), key, "abci_query", queryParams(path, height),
+		func() interface{} { return new(types2.ResultABCIQuery) },
+		func(result interface{}) (int64, string) {
+			response := result.(*types2.ResultABCIQuery).Response
+			return response.Height, hex.EncodeToString(response.Value)
+		})
+	if err != nil {
+		return
+	}
+	response := result.(*types2.ResultABCIQuery).Response
+	if err = checkQueryHeight(response, height); err != nil {
+		return
+	}
+
+	return response.Value, nil
+}
+
+// DoQuorumQueryAndParse - query state of key at height with quorum and parse it
+func DoQuorumQueryAndParse(c *Chain, key string, height int64, data interface{}) (err error) {
+
+	value, err := DoQuorumQuery(c, key, height)
+	if err != nil {
+		return
+	}
+
+	return json.Unmarshal(value, data)
+}
+
+// DoQuorumTx - get transaction of hash from quorumSize nodes if quorum mode is on
+func DoQuorumTx(nodeAddrSlice []string, hash string, result *core_types.ResultTx) (err error) {
+	params := map[string]interface{}{"hash": hash}
+
+	k := quorumSize()
+	if k <= 1 {
+		return DoHttpRequestAndParseEx(nodeAddrSlice, "tx", params, result)
+	}
+
+	// all nodes have the same height of transaction, lag of nodes does not matter
+	agreed, err := GetNodePool(nodeAddrSlice).QuorumCall(k, -1, "tx "+hash, "tx", params,
+		func() interface{} { return new(core_types.ResultTx) },
+		func(result interface{}) (int64, string) {
+			tx := result.(*core_types.ResultTx)
+			data, _ := json.Marshal(tx.DeliverResult)
+			return tx.Height, fmt.Sprintf("height=%d,index=%d,deliverTx=%s", tx.Height, tx.Index, data)
+		})
+	if err != nil {
+		return
+	}
+	*result = *agreed.(*core_types.ResultTx)
+
+	return
+}
+
+// QuorumCall - call method on k nodes and compare answers, the result is returned only if all values are the same
+// and heights differ at most maxLag (negative maxLag means heights are not compared), an error response is an answer too
+func (p *NodePool) QuorumCall(k int, maxLag int64, target, method string, params map[string]interface{},
+	newResult func() interface{}, answer func(result interface{}) (height int64, value string)) (interface{}, error) {
+
+	answers, nodes, results, err := p.collect(k, method, params, newResult, answer)
+	if err != nil {
+		return nil, err
+	}
+
+	if agreed(answers, maxLag) {
+		p.recordQuorum(nil)
+		if answers[0].Error != "" {
+			return nil, errors.New(answers[0].Error)
+		}
+		return results[0], nil
+	}
+
+	// nodes that differ from the most common answer are counted as divergent
+	counts := make(map[string]int)
+	majority := ""
+	for _, a := range answers {
+		counts[a.Value+a.Error]++
+		if counts[a.Value+a.Error] > counts[majority] {
+			majority = a.Value + a.Error
+		}
+	}
+	divergent := make([]*poolNode, 0)
+	for i, a := range answers {
+		if a.Value+a.Error != majority || (maxLag >= 0 && lagOf(answers, a.Height) > maxLag) {
+			divergent = append(divergent, nodes[i])
+		}
+	}
+	p.recordQuorum(divergent)
+
+	err = &DivergenceError{Target: target, Answers: answers}
+	if logger != nil {
+		reads, divergences := p.QuorumStats()
+		logger.Warn("Quorum read diverged", "error", err.Error(), "quorumReads", reads, "quorumDivergences", divergences)
+	}
+
+	return nil, err
+}
+
+// CommonHeight - the highest block that all of k nodes have, the latest state of it can be read from every one of them
+func (p *NodePool) CommonHeight(k int) (int64, error) {
+	answers, _, _, err := p.collect(k, "abci_info", map[string]interface{}{},
+		func() interface{} { return new(core_types.ResultABCIInfo) },
+		func(result interface{}) (int64, string) {
+			return result.(*core_types.ResultABCIInfo).Response.LastBlockHeight, ""
+		})
+	if err != nil {
+		return 0, err
+	}
+
+	height := int64(0)
+	for _, a := range answers {
+		if a.Error != "" {
+			return 0, errors.New(a.Error)
+		}
+		if height == 0 || a.Height < height {
+			height = a.Height
+		}
+	}
+
+	return height, nil
+}
+
+// collect - call method on k nodes, nodes that cannot be reached are replaced by next candidates
+func (p *NodePool) collect(k int, method string, params map[string]interface{},
+	newResult func() interface{}, answer func(result interface{}) (int64, string)) ([]QuorumAnswer, []*poolNode, []interface{}, error) {
+
+	candidates := p.candidates(time.Now())
+	if len(candidates) < k {
+		return nil, nil, nil, fmt.Errorf("Quorum of %d nodes cannot be reached, only %d nodes are available ", k, len(candidates))
+	}
+
+	answers := make([]QuorumAnswer, 0, k)
+	nodes := make([]*poolNode, 0, k)
+	results := make([]interface{}, 0, k)
+	for next := 0; len(answers) < k && next < len(candidates); {
+		batch := candidates[next:]
+		if len(batch) > k-len(answers) {
+			batch = batch[:k-len(answers)]
+		}
+		next += len(batch)
+
+		batchAnswers := make([]*QuorumAnswer, len(batch))
+		batchResults := make([]interface{}, len(batch))
+		var wg sync.WaitGroup
+		for i, node := range batch {
+			wg.Add(1)
+			go func(i int, node *poolNode) {
+				defer wg.Done()
+				batchResults[i] = newResult()
+				batchAnswers[i] = p.quorumAnswer(node, method, params, batchResults[i], answer)
+			}(i, node)
+		}
+		wg.Wait()
+
+		for i, a := range batchAnswers {
+			if a != nil {
+				answers = append(answers, *a)
+				nodes = append(nodes, batch[i])
+				results = append(results, batchResults[i])
+			}
+		}
+	}
+
+	if len(answers) < k {
+		return nil, nil, nil, fmt.Errorf("Quorum of %d nodes cannot be reached, only %d nodes answered ", k, len(answers))
+	}
+
+	return answers, nodes, results, nil
+}
+
+// quorumAnswer - answer of node, nil if the node cannot be reached
+func (p *NodePool) quorumAnswer(node *poolNode, method string, params map[string]interface{}, result interface{},
+	answer func(result interface{}) (int64, string)) *QuorumAnswer {
+
+	p.mtx.RLock()
+	nodeURL, client := node.url, node.client
+	p.mtx.RUnlock()
+
+	start := time.Now()
+	_, err := client.Call(method, params, result)
+	if _, ok := err.(*url.Error); ok {
+		p.recordFailure(node, err)
+		reportNodeError(nodeURL, err)
+		return nil
+	}
+	p.recordSuccess(node, time.Since(start))
+
+	a := &QuorumAnswer{Node: nodeURL}
+	if err != nil {
+		a.Error = trimError(err).Error()
+	} else {
+		a.Height, a.Value = answer(result)
+	}
+
+	return a
+}
+
+// agreed - all answers have the same value and error, and their heights are close enough
+func agreed(answers []QuorumAnswer, maxLag int64) bool {
+	for _, a := range answers[1:] {
+		if a.Value != answers[0].Value || a.Error != answers[0].Error {
+			return false
+		}
+	}
+
+	return maxLag < 0 || lagOf(answers, answers[0].Height) <= maxLag
+}
+
+// lagOf - difference between height and the highest height of answers
+func lagOf(answers []QuorumAnswer, height int64) int64 {
+	highest := height
+	for _, a := range answers {
+		if a.Height > highest {
+			highest = a.Height
+		}
+	}
+
+	return highest - height
+}
+
+func (p *NodePool) recordQuorum(divergent []*poolNode) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	p.quorumReads++
+	if len(divergent) > 0 {
+		p.quorumDivergences++
+	}
+	for _, node := range divergent {
+		node.divergences++
+	}
+}
+
+// QuorumStats - count of quorum reads and count of them that nodes diverged
+func (p *NodePool) QuorumStats() (reads, divergences uint64) {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+
+	return p.quorumReads, p.quorumDivergences
+}
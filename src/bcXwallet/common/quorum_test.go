@@ -0,0 +1,129 @@
+package common
+
+import (
+	"bcXwallet/common/config"
+	types2 "blockchain/abciapp_v1.0/types"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testQueryNode - node that answers abci_query with value at height
+func testQueryNode(value string, height int64) *httptest.Server {
+	var calls int32
+	data, _ := hex.DecodeString(value)
+	return testNode(&calls, fmt.Sprintf(`{"response":{"code":200,"value":"%s","height":%d}}`, base64.StdEncoding.EncodeToString(data), height))
+}
+
+func TestQuorumCall(t *testing.T) {
+	assert := assert.New(t)
+
+	nodeA := testQueryNode("01", 10)
+	defer nodeA.Close()
+	nodeB := testQueryNode("01", 11)
+	defer nodeB.Close()
+	nodeC := testQueryNode("02", 11)
+	defer nodeC.Close()
+	nodeD := testQueryNode("01", 20)
+	defer nodeD.Close()
+
+	call := func(pool *NodePool, k int) (interface{}, error) {
+		return pool.QuorumCall(k, 3, "/account/ex/test", "abci_query", map[string]interface{}{"path": "/account/ex/test"},
+			func() interface{} { return new(types2.ResultABCIQuery) },
+			func(result interface{}) (int64, string) {
+				response := result.(*types2.ResultABCIQuery).Response
+				return response.Height, hex.EncodeToString(response.Value)
+			})
+	}
+
+	// agreed value
+	pool := NewNodePool([]string{nodeA.URL, nodeB.URL, nodeC.URL})
+	result, err := call(pool, 2)
+	assert.Nil(err)
+	if assert.NotNil(result) {
+		assert.Equal([]byte{1}, result.(*types2.ResultABCIQuery).Response.Value)
+	}
+
+	// different value
+	result, err = call(pool, 3)
+	assert.Nil(result)
+	if divergence, ok := err.(*DivergenceError); assert.True(ok) {
+		assert.Equal(3, len(divergence.Answers))
+		assert.Contains(divergence.Error(), nodeC.URL+" at height 11 returned 02")
+	}
+	assert.Equal(uint64(1), pool.Nodes()[2].Divergences)
+	assert.Equal(uint64(0), pool.Nodes()[0].Divergences)
+
+	// the same value with lagging node
+	pool = NewNodePool([]string{nodeA.URL, nodeD.URL})
+	_, err = call(pool, 2)
+	_, ok := err.(*DivergenceError)
+	assert.True(ok)
+	assert.Equal(uint64(1), pool.Nodes()[0].Divergences)
+
+	reads, divergences := pool.QuorumStats()
+	assert.Equal(uint64(1), reads)
+	assert.Equal(uint64(1), divergences)
+
+	// not enough nodes
+	_, err = call(pool, 3)
+	assert.NotNil(err)
+}
+
+// testStateNode - node at lastBlock whose state of key at every height is in values
+func testStateNode(lastBlock int64, values map[int64]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Method string
+			Params map[string]json.RawMessage
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+
+		result := fmt.Sprintf(`{"response":{"last_block_height":%d}}`, lastBlock)
+		if request.Method == "abci_query" {
+			height := lastBlock
+			if h, ok := request.Params["height"]; ok {
+				height, _ = strconv.ParseInt(strings.Trim(string(h), `"`), 10, 64)
+			}
+			data, _ := hex.DecodeString(values[height])
+			result = fmt.Sprintf(`{"response":{"code":200,"value":"%s","height":%d}}`, base64.StdEncoding.EncodeToString(data), height)
+		}
+		w.Write([]byte(`{"jsonrpc":"2.0","id":"jsonrpc-client","result":` + result + `}`))
+	}))
+}
+
+func TestDoQuorumQueryAtCommonHeight(t *testing.T) {
+	assert := assert.New(t)
+
+	size := bcbXWalletConfig.QuorumSize
+	bcbXWalletConfig.QuorumSize = 2
+	defer func() { bcbXWalletConfig.QuorumSize = size }()
+
+	// honest nodes one block apart, the state changes at the newer block
+	nodeA := testStateNode(10, map[int64]string{10: "01"})
+	defer nodeA.Close()
+	nodeB := testStateNode(11, map[int64]string{10: "01", 11: "02"})
+	defer nodeB.Close()
+
+	c := NewChain(config.ChainProfile{Name: "quorum", ChainID: "bcb", NodeAddrSlice: []string{nodeA.URL, nodeB.URL}})
+	height, err := GetNodePool(c.NodeAddrSlice).CommonHeight(2)
+	assert.Nil(err)
+	assert.Equal(int64(10), height)
+
+	value, err := DoQuorumQuery(c, "/account/ex/test", 0)
+	assert.Nil(err)
+	assert.Equal([]byte{1}, value)
+
+	// the lagging node has no state of the newer block
+	value, err = DoQuorumQuery(c, "/account/ex/test", 11)
+	assert.NotNil(err)
+	assert.Nil(value)
+}
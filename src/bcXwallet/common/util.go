@@ -63,9 +63,14 @@ func IntToByte(val int64) []byte {
 	return buf
 }
 
-func JudgeFloatStr(val string) {
+// IsFloatStr - check val is a non-negative decimal string with at most 9 fractional digits
+func IsFloatStr(val string) (bool, error) {
 	pattern := `^\d+(\.\d{0,9})?$`
-	valid, err := regexp.Match(pattern, []byte(val))
+	return regexp.Match(pattern, []byte(val))
+}
+
+func JudgeFloatStr(val string) {
+	valid, err := IsFloatStr(val)
 	if err != nil {
 		logger.Info("Regular expression error")
 		panic(" Regular expression error")
@@ -113,6 +118,27 @@ func FloatStrToBigInt(val string) big.Int {
 	return *valInt.Add(valInt, valFloat)
 }
 
+// BigIntToFloatStr - format an integer count of cong as a decimal string with at most 9 fractional digits
+func BigIntToFloatStr(val *big.Int) string {
+	sign := ""
+	abs := new(big.Int).Set(val)
+	if abs.Sign() < 0 {
+		sign = "-"
+		abs.Neg(abs)
+	}
+
+	intPart, fracPart := new(big.Int).QuoRem(abs, big.NewInt(1E9), new(big.Int))
+	if fracPart.Sign() == 0 {
+		return sign + intPart.String()
+	}
+
+	fracStr := fracPart.String()
+	fracStr = strings.Repeat("0", 9-len(fracStr)) + fracStr
+	fracStr = strings.TrimRight(fracStr, "0")
+
+	return sign + intPart.String() + "." + fracStr
+}
+
 func CurrentDirectory() (string, error) {
 	file, err := exec.LookPath(os.Args[0])
 	if err != nil {
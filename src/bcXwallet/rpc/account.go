@@ -25,9 +25,8 @@ type Account struct {
 	Hash          []byte       `json:"hash"`
 }
 
-func newAccount(name, password string) (*Account, []byte, error) {
-	cfg := common.GetConfig()
-	isExist, _ := db.IsExist(name)
+func newAccount(c *common.Chain, name, password string) (*Account, []byte, error) {
+	isExist, _ := db.IsExist(c.KeyStoreNamespace, name)
 	if isExist {
 		return nil, nil, errors.New("The account of " + name + " is already exist!")
 	}
@@ -40,7 +39,7 @@ func newAccount(name, password string) (*Account, []byte, error) {
 
 	acct := Account{
 		Name:          name,
-		Address:       priKey.PubKey().Address(cfg.ChainID),
+		Address:       priKey.PubKey().Address(c.ChainID),
 		EncPrivateKey: priKeyWithPWBytes,
 		PrivateKey:    priKeyByte,
 	}
@@ -48,8 +47,19 @@ func newAccount(name, password string) (*Account, []byte, error) {
 	return &acct, accessKey, nil
 }
 
-func (acct *Account) Save(accessKey []byte) error {
-	return db.SetAccount(acct, accessKey)
+func (acct *Account) Save(namespace string, accessKey []byte) error {
+	return db.SetAccount(namespace, acct, accessKey)
+}
+
+// chainAccount - get account in keystore namespace of chain, its address is derived with chain ID of chain
+func chainAccount(c *common.Chain, name string, accessKey []byte) (*Account, error) {
+	acct, err := db.Account(c.KeyStoreNamespace, name, accessKey)
+	if err != nil {
+		return nil, err
+	}
+	acct.Address = crypto.PrivKeyEd25519FromBytes(acct.PrivateKey).PubKey().Address(c.ChainID)
+
+	return acct, nil
 }
 
 // GetPassword will prompt for a password one-time (to sign a tx)
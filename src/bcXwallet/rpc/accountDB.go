@@ -2,11 +2,14 @@ package rpc
 
 import (
 	"bcXwallet/common"
+	"blockchain/abciapp_v1.0/keys"
 	"blockchain/algorithm"
+	"bytes"
 	"common/bcdb"
 	"errors"
 	"fmt"
 	"path/filepath"
+	"time"
 )
 
 type DB struct {
@@ -22,12 +25,91 @@ var (
 	dbName = "account"
 )
 
-func keyOfAccountNumber() []byte {
-	return []byte("/bcbXWallet/accountNumber")
+// keyOfWallet - wallets of legacy namespace "" are keyed by their name
+func keyOfWallet(namespace, name string) []byte {
+	if namespace == "" {
+		return []byte(name)
+	}
+
+	return []byte("/bcbXWallet/namespace/" + namespace + "/wallet/" + name)
+}
+
+func keyOfAccountNumber(namespace string) []byte {
+	if namespace == "" {
+		return []byte("/bcbXWallet/accountNumber")
+	}
+
+	return []byte("/bcbXWallet/namespace/" + namespace + "/accountNumber")
+}
+
+func keyOfWalletList(namespace string, pageNumber uint64) []byte {
+	if namespace == "" {
+		return []byte(fmt.Sprintf("/bcbXWallet/walletList/page%d", pageNumber))
+	}
+
+	return []byte(fmt.Sprintf("/bcbXWallet/namespace/%s/walletList/page%d", namespace, pageNumber))
+}
+
+func keyOfRequest(requestID string) []byte {
+	return []byte("/bcbXWallet/request/" + requestID)
+}
+
+func keyOfMultisigTx(id string) []byte {
+	return []byte("/bcbXWallet/multisig/" + id)
+}
+
+// keyOfFollower - key of data followed from blocks of chain, keys of the top-level chain in config have no chain
+// in them, so data it followed before multiple chains are supported is kept
+func keyOfFollower(chain, suffix string) []byte {
+	if chain == common.GetConfig().ChainID {
+		return []byte("/bcbXWallet/" + suffix)
+	}
+
+	return []byte("/bcbXWallet/chain/" + chain + "/" + suffix)
+}
+
+func keyOfIndexedHeight(chain string) []byte {
+	return keyOfFollower(chain, "indexer/height")
+}
+
+func keyOfHistory(chain, address, suffix string) []byte {
+	return keyOfFollower(chain, "history/"+address+"/"+suffix)
+}
+
+func keyOfDepositScannedHeight(chain string) []byte {
+	return keyOfFollower(chain, "deposit/height")
+}
+
+func keyOfDepositLastID(chain string) []byte {
+	return keyOfFollower(chain, "deposit/lastId")
+}
+
+func keyOfDeposit(chain string, id uint64) []byte {
+	return keyOfFollower(chain, fmt.Sprintf("deposit/item/%020d", id))
+}
+
+func keyOfDepositNotifiedID(chain string) []byte {
+	return keyOfFollower(chain, "deposit/notifiedId")
+}
+
+func keyOfWebhookLastID() []byte {
+	return []byte("/bcbXWallet/webhook/lastId")
+}
+
+func keyOfWebhookEvent(id uint64) []byte {
+	return []byte(fmt.Sprintf("/bcbXWallet/webhook/event/%020d", id))
+}
+
+func keyOfWebhookDelivery(eventID uint64, index int) []byte {
+	return []byte(fmt.Sprintf("/bcbXWallet/webhook/outbox/%020d/%03d", eventID, index))
 }
 
-func keyOfWalletList(pageNumber uint64) []byte {
-	return []byte(fmt.Sprintf("/bcbXWallet/walletList/page%d", pageNumber))
+func keyOfReconciledHeight(chain string) []byte {
+	return keyOfFollower(chain, "reconcile/height")
+}
+
+func keyOfReconcileReport(chain string, height int64) []byte {
+	return keyOfFollower(chain, fmt.Sprintf("reconcile/report/%020d", height))
 }
 
 // Init DB
@@ -56,11 +138,11 @@ func absolutePath(path string) string {
 	return path
 }
 
-// IsExist - get true of account is exist or not
-func (db *DB) IsExist(name string) (bool, error) {
+// IsExist - get true of account is exist in keystore namespace or not
+func (db *DB) IsExist(namespace, name string) (bool, error) {
 
 	//获取账户信息
-	acctBytes, err := db.Get([]byte(name))
+	acctBytes, err := db.Get(keyOfWallet(namespace, name))
 	if err != nil {
 		return false, err
 	}
@@ -72,11 +154,11 @@ func (db *DB) IsExist(name string) (bool, error) {
 	return true, nil
 }
 
-// Account - get account info with name and accessKey
-func (db *DB) Account(name string, accessKey []byte) (*Account, error) {
+// Account - get account info in keystore namespace with name and accessKey
+func (db *DB) Account(namespace, name string, accessKey []byte) (*Account, error) {
 
 	//获取账户信息
-	acctBytes, err := db.Get([]byte(name))
+	acctBytes, err := db.Get(keyOfWallet(namespace, name))
 	if err != nil {
 		return nil, err
 	}
@@ -98,7 +180,7 @@ func (db *DB) Account(name string, accessKey []byte) (*Account, error) {
 	return &acct, nil
 }
 
-func (db *DB) SetAccount(acct *Account, accessKey []byte) error {
+func (db *DB) SetAccount(namespace string, acct *Account, accessKey []byte) error {
 
 	jsonBytes, err := cdc.MarshalJSON(acct)
 	if err != nil {
@@ -106,14 +188,14 @@ func (db *DB) SetAccount(acct *Account, accessKey []byte) error {
 	}
 	walBytes := algorithm.EncryptWithPassword(jsonBytes, nil, accessKey)
 
-	acctNumber, err := db.AccountNumber()
+	acctNumber, err := db.AccountNumber(namespace)
 	if err != nil {
 		panic(err)
 	}
 
 	pageNumber := acctNumber/countOfOnePage + 1
 
-	walletList, err := db.WalletList(pageNumber)
+	walletList, err := db.WalletList(namespace, pageNumber)
 	if err != nil {
 		return err
 	}
@@ -132,16 +214,16 @@ func (db *DB) SetAccount(acct *Account, accessKey []byte) error {
 
 	// batch set
 	dbBatch := db.NewBatch()
-	dbBatch.Set([]byte(acct.Name), walBytes)
-	dbBatch.Set([]byte(keyOfWalletList(pageNumber)), jsonList)
-	dbBatch.Set([]byte(keyOfAccountNumber()), jsonCount)
+	dbBatch.Set(keyOfWallet(namespace, acct.Name), walBytes)
+	dbBatch.Set(keyOfWalletList(namespace, pageNumber), jsonList)
+	dbBatch.Set(keyOfAccountNumber(namespace), jsonCount)
 
 	return dbBatch.Commit()
 }
 
-func (db *DB) WalletList(pageNumber uint64) ([]string, error) {
+func (db *DB) WalletList(namespace string, pageNumber uint64) ([]string, error) {
 
-	bytes, err := db.Get(keyOfWalletList(pageNumber))
+	bytes, err := db.Get(keyOfWalletList(namespace, pageNumber))
 	if err != nil {
 		return nil, err
 	}
@@ -156,9 +238,9 @@ func (db *DB) WalletList(pageNumber uint64) ([]string, error) {
 	return list, err
 }
 
-func (db *DB) AccountNumber() (uint64, error) {
+func (db *DB) AccountNumber(namespace string) (uint64, error) {
 
-	bytes, err := db.Get(keyOfAccountNumber())
+	bytes, err := db.Get(keyOfAccountNumber(namespace))
 	if err != nil {
 		return 0, err
 	}
@@ -172,3 +254,450 @@ func (db *DB) AccountNumber() (uint64, error) {
 
 	return number, err
 }
+
+// RequestRecord - get record of requestId, return nil if it does not exist
+func (db *DB) RequestRecord(requestID string) (*requestRecord, error) {
+
+	bytes, err := db.Get(keyOfRequest(requestID))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(bytes) == 0 {
+		return nil, nil
+	}
+
+	record := new(requestRecord)
+	err = cdc.UnmarshalJSON(bytes, record)
+
+	return record, err
+}
+
+func (db *DB) SetRequestRecord(requestID string, record *requestRecord) error {
+
+	jsonBytes, err := cdc.MarshalJSON(record)
+	if err != nil {
+		return err
+	}
+
+	return db.SetSync(keyOfRequest(requestID), jsonBytes)
+}
+
+// MultisigTx - get multi-signature transaction with id, return nil if it does not exist
+func (db *DB) MultisigTx(id string) (*MultisigTxResult, error) {
+
+	bytes, err := db.Get(keyOfMultisigTx(id))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(bytes) == 0 {
+		return nil, nil
+	}
+
+	multisigTx := new(MultisigTxResult)
+	err = cdc.UnmarshalJSON(bytes, multisigTx)
+
+	return multisigTx, err
+}
+
+func (db *DB) SetMultisigTx(multisigTx *MultisigTxResult) error {
+
+	jsonBytes, err := cdc.MarshalJSON(multisigTx)
+	if err != nil {
+		return err
+	}
+
+	return db.SetSync(keyOfMultisigTx(multisigTx.ID), jsonBytes)
+}
+
+// IndexedHeight - get the last height of chain indexed by address history indexer
+func (db *DB) IndexedHeight(chain string) (int64, error) {
+
+	bytes, err := db.Get(keyOfIndexedHeight(chain))
+	if err != nil {
+		return 0, err
+	}
+
+	if len(bytes) == 0 {
+		return 0, nil
+	}
+
+	height := int64(0)
+	err = cdc.UnmarshalJSON(bytes, &height)
+
+	return height, err
+}
+
+// SaveHistory - save history entries of block of chain and set checkpoint to height in one batch
+func (db *DB) SaveHistory(chain string, height int64, entries []historyEntry) error {
+
+	dbBatch := db.NewBatch()
+	for _, entry := range entries {
+		jsonBytes, err := cdc.MarshalJSON(entry.Item)
+		if err != nil {
+			return err
+		}
+		dbBatch.Set(keyOfHistory(chain, entry.Address, entry.Suffix), jsonBytes)
+	}
+
+	jsonHeight, err := cdc.MarshalJSON(height)
+	if err != nil {
+		return err
+	}
+	dbBatch.Set(keyOfIndexedHeight(chain), jsonHeight)
+
+	return dbBatch.CommitSync()
+}
+
+// History - get history items of address on chain in [fromHeight, toHeight] after cursor, toHeight 0 means no limit,
+// nextCursor is not empty if there are more items
+func (db *DB) History(chain string, address, token keys.Address, fromHeight, toHeight int64, cursor string, limit int) (items []HistoryItem, nextCursor string, err error) {
+
+	prefix := keyOfHistory(chain, address, "")
+	start := keyOfHistory(chain, address, fmt.Sprintf(historyHeightKeyFormat, fromHeight))
+	if cursor != "" {
+		if cursorKey := append(keyOfHistory(chain, address, cursor), 0); bytes.Compare(cursorKey, start) > 0 {
+			start = cursorKey
+		}
+	}
+
+	// the key after all keys with prefix, the last byte of prefix is '/'
+	end := append(prefix[:len(prefix)-1:len(prefix)-1], '0')
+	if toHeight > 0 {
+		end = keyOfHistory(chain, address, fmt.Sprintf(historyHeightKeyFormat, toHeight+1))
+	}
+
+	items = make([]HistoryItem, 0)
+	var lastKey []byte
+
+	iter := db.NewIterator(start, end)
+	defer iter.Release()
+	for iter.Next() {
+		var item HistoryItem
+		if err = cdc.UnmarshalJSON(iter.Value(), &item); err != nil {
+			return
+		}
+
+		if token != "" && item.Token != token {
+			continue
+		}
+
+		if len(items) == limit {
+			nextCursor = string(lastKey[len(prefix):])
+			break
+		}
+
+		items = append(items, item)
+		lastKey = append(lastKey[:0], iter.Key()...)
+	}
+
+	err = iter.Error()
+
+	return
+}
+
+// DepositScannedHeight - get the last height of chain scanned by deposit watcher
+func (db *DB) DepositScannedHeight(chain string) (int64, error) {
+
+	bytes, err := db.Get(keyOfDepositScannedHeight(chain))
+	if err != nil {
+		return 0, err
+	}
+
+	if len(bytes) == 0 {
+		return 0, nil
+	}
+
+	height := int64(0)
+	err = cdc.UnmarshalJSON(bytes, &height)
+
+	return height, err
+}
+
+// SaveDeposits - assign id to deposits of block of chain in place, save them, their detected events with deliveries
+// to urls and set scanned height to height in one batch, no event is saved if urls is empty
+func (db *DB) SaveDeposits(chain string, height int64, deposits []Deposit, urls []string) error {
+
+	lastID := uint64(0)
+	bytes, err := db.Get(keyOfDepositLastID(chain))
+	if err != nil {
+		return err
+	}
+	if len(bytes) != 0 {
+		if err = cdc.UnmarshalJSON(bytes, &lastID); err != nil {
+			return err
+		}
+	}
+
+	dbBatch := db.NewBatch()
+	events := make([]*WebhookEvent, 0, len(deposits))
+	for i := range deposits {
+		lastID++
+		deposits[i].ID = lastID
+		jsonBytes, err := cdc.MarshalJSON(deposits[i])
+		if err != nil {
+			return err
+		}
+		dbBatch.Set(keyOfDeposit(chain, lastID), jsonBytes)
+		events = append(events, &WebhookEvent{Type: eventDepositDetected, Chain: chain, Time: time.Now().Unix(), Data: string(jsonBytes)})
+	}
+
+	jsonID, err := cdc.MarshalJSON(lastID)
+	if err != nil {
+		return err
+	}
+	dbBatch.Set(keyOfDepositLastID(chain), jsonID)
+
+	jsonHeight, err := cdc.MarshalJSON(height)
+	if err != nil {
+		return err
+	}
+	dbBatch.Set(keyOfDepositScannedHeight(chain), jsonHeight)
+
+	if len(urls) != 0 {
+		if err = db.setWebhookEvents(dbBatch, events, urls); err != nil {
+			return err
+		}
+	}
+
+	return dbBatch.CommitSync()
+}
+
+// Deposits - get deposits of chain with id greater than sinceID, acked deposits are skipped unless includeAcked is true
+func (db *DB) Deposits(chain string, sinceID uint64, limit int, includeAcked bool) ([]Deposit, error) {
+
+	deposits := make([]Deposit, 0)
+
+	// the key after all deposit items, keys of items start with "deposit/item/"
+	iter := db.NewIterator(keyOfDeposit(chain, sinceID+1), keyOfFollower(chain, "deposit/item0"))
+	defer iter.Release()
+	for iter.Next() && len(deposits) < limit {
+		var deposit Deposit
+		if err := cdc.UnmarshalJSON(iter.Value(), &deposit); err != nil {
+			return nil, err
+		}
+
+		if includeAcked || !deposit.Acked {
+			deposits = append(deposits, deposit)
+		}
+	}
+
+	return deposits, iter.Error()
+}
+
+// Deposit - get deposit of chain with id, return nil if it does not exist
+func (db *DB) Deposit(chain string, id uint64) (*Deposit, error) {
+
+	bytes, err := db.Get(keyOfDeposit(chain, id))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(bytes) == 0 {
+		return nil, nil
+	}
+
+	deposit := new(Deposit)
+	err = cdc.UnmarshalJSON(bytes, deposit)
+
+	return deposit, err
+}
+
+func (db *DB) SetDeposit(chain string, deposit *Deposit) error {
+
+	// confirmations are calculated when deposit is read
+	saved := *deposit
+	saved.Confirmations = 0
+	saved.Confirmed = false
+
+	jsonBytes, err := cdc.MarshalJSON(saved)
+	if err != nil {
+		return err
+	}
+
+	return db.SetSync(keyOfDeposit(chain, deposit.ID), jsonBytes)
+}
+
+// DepositNotifiedID - get id of the last deposit of chain that confirmed event is emitted
+func (db *DB) DepositNotifiedID(chain string) (uint64, error) {
+
+	bytes, err := db.Get(keyOfDepositNotifiedID(chain))
+	if err != nil || len(bytes) == 0 {
+		return 0, err
+	}
+
+	id := uint64(0)
+	err = cdc.UnmarshalJSON(bytes, &id)
+
+	return id, err
+}
+
+func (db *DB) SetDepositNotifiedID(chain string, id uint64) error {
+
+	jsonBytes, err := cdc.MarshalJSON(id)
+	if err != nil {
+		return err
+	}
+
+	return db.SetSync(keyOfDepositNotifiedID(chain), jsonBytes)
+}
+
+// SaveWebhookEvent - assign id to event in place, save it and its deliveries to urls in one batch
+func (db *DB) SaveWebhookEvent(event *WebhookEvent, urls []string) error {
+
+	dbBatch := db.NewBatch()
+	if err := db.setWebhookEvents(dbBatch, []*WebhookEvent{event}, urls); err != nil {
+		return err
+	}
+
+	return dbBatch.CommitSync()
+}
+
+// setWebhookEvents - assign id to events in place, set them and their deliveries to urls in dbBatch
+func (db *DB) setWebhookEvents(dbBatch *bcdb.GILevelDBBatch, events []*WebhookEvent, urls []string) error {
+
+	lastID := uint64(0)
+	bytes, err := db.Get(keyOfWebhookLastID())
+	if err != nil {
+		return err
+	}
+	if len(bytes) != 0 {
+		if err = cdc.UnmarshalJSON(bytes, &lastID); err != nil {
+			return err
+		}
+	}
+
+	for _, event := range events {
+		lastID++
+		event.ID = lastID
+
+		jsonEvent, err := cdc.MarshalJSON(event)
+		if err != nil {
+			return err
+		}
+		dbBatch.Set(keyOfWebhookEvent(event.ID), jsonEvent)
+
+		for i, url := range urls {
+			jsonDelivery, err := cdc.MarshalJSON(webhookDelivery{EventID: event.ID, Index: i, URL: url})
+			if err != nil {
+				return err
+			}
+			dbBatch.Set(keyOfWebhookDelivery(event.ID, i), jsonDelivery)
+		}
+	}
+
+	jsonID, err := cdc.MarshalJSON(lastID)
+	if err != nil {
+		return err
+	}
+	dbBatch.Set(keyOfWebhookLastID(), jsonID)
+
+	return nil
+}
+
+// WebhookEvent - get event with id, return nil if it does not exist
+func (db *DB) WebhookEvent(id uint64) (*WebhookEvent, error) {
+
+	bytes, err := db.Get(keyOfWebhookEvent(id))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(bytes) == 0 {
+		return nil, nil
+	}
+
+	event := new(WebhookEvent)
+	err = cdc.UnmarshalJSON(bytes, event)
+
+	return event, err
+}
+
+// WebhookDeliveries - get at most limit deliveries in outbox that are due at dueTime in order of event id,
+// deliveries waiting for retry are skipped and not counted, so they never hold back newer events
+func (db *DB) WebhookDeliveries(dueTime int64, limit int) ([]webhookDelivery, error) {
+
+	deliveries := make([]webhookDelivery, 0)
+
+	iter := db.NewPrefixIterator([]byte("/bcbXWallet/webhook/outbox/"))
+	defer iter.Release()
+	for iter.Next() && len(deliveries) < limit {
+		var delivery webhookDelivery
+		if err := cdc.UnmarshalJSON(iter.Value(), &delivery); err != nil {
+			return nil, err
+		}
+		if delivery.NextTime > dueTime {
+			continue
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, iter.Error()
+}
+
+func (db *DB) SetWebhookDelivery(delivery *webhookDelivery) error {
+
+	jsonBytes, err := cdc.MarshalJSON(delivery)
+	if err != nil {
+		return err
+	}
+
+	return db.SetSync(keyOfWebhookDelivery(delivery.EventID, delivery.Index), jsonBytes)
+}
+
+func (db *DB) DeleteWebhookDelivery(delivery *webhookDelivery) error {
+	return db.DeleteSync(keyOfWebhookDelivery(delivery.EventID, delivery.Index))
+}
+
+// ReconciledHeight - get height of the last snapshot of chain taken by reconciler
+func (db *DB) ReconciledHeight(chain string) (int64, error) {
+
+	bytes, err := db.Get(keyOfReconciledHeight(chain))
+	if err != nil || len(bytes) == 0 {
+		return 0, err
+	}
+
+	height := int64(0)
+	err = cdc.UnmarshalJSON(bytes, &height)
+
+	return height, err
+}
+
+// SaveReconcileReport - save report of chain with its snapshot and set reconciled height to its height in one batch
+func (db *DB) SaveReconcileReport(chain string, report *ReconcileReportResult) error {
+
+	jsonBytes, err := cdc.MarshalJSON(report)
+	if err != nil {
+		return err
+	}
+
+	jsonHeight, err := cdc.MarshalJSON(report.Height)
+	if err != nil {
+		return err
+	}
+
+	dbBatch := db.NewBatch()
+	dbBatch.Set(keyOfReconcileReport(chain, report.Height), jsonBytes)
+	dbBatch.Set(keyOfReconciledHeight(chain), jsonHeight)
+
+	return dbBatch.CommitSync()
+}
+
+// ReconcileReport - get report of snapshot of chain at height, return nil if it does not exist
+func (db *DB) ReconcileReport(chain string, height int64) (*ReconcileReportResult, error) {
+
+	bytes, err := db.Get(keyOfReconcileReport(chain, height))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(bytes) == 0 {
+		return nil, nil
+	}
+
+	report := new(ReconcileReportResult)
+	err = cdc.UnmarshalJSON(bytes, report)
+
+	return report, err
+}
@@ -0,0 +1,85 @@
+package rpc
+
+import (
+	"bcXwallet/common"
+	"errors"
+	"math/big"
+)
+
+// checkAmountUnit - amountUnit can be empty(means cong), cong or token
+func checkAmountUnit(amountUnit string) error {
+	if amountUnit != "" && amountUnit != amountUnitCong && amountUnit != amountUnitToken {
+		return errors.New("AmountUnit must be cong or token ")
+	}
+
+	return nil
+}
+
+// valueToCong - convert value in amountUnit to an integer count of cong
+func valueToCong(value, amountUnit string) (string, error) {
+	if amountUnit != amountUnitToken {
+		return value, nil
+	}
+
+	valid, err := common.IsFloatStr(value)
+	if err != nil {
+		return "", err
+	}
+	if !valid {
+		return "", errors.New("Value must be decimal with at most 9 digits after point ")
+	}
+
+	cong := common.FloatStrToBigInt(value)
+	return cong.String(), nil
+}
+
+// congToToken - format an integer count of cong to token decimal string
+func congToToken(cong string) string {
+	if cong == "" {
+		return ""
+	}
+
+	value, ok := new(big.Int).SetString(cong, 10)
+	if !ok {
+		return ""
+	}
+
+	return common.BigIntToFloatStr(value)
+}
+
+// fillTxResultDecimal - fill the decimal amounts of transaction
+func fillTxResultDecimal(tx *TxResult) {
+	tx.Unit = amountUnitToken
+	tx.FeeDecimal = congToToken(new(big.Int).SetUint64(tx.Fee).String())
+	for i := range tx.Messages {
+		tx.Messages[i].ValueDecimal = congToToken(tx.Messages[i].Value)
+	}
+	for i := range tx.Receipts {
+		tx.Receipts[i].ValueDecimal = congToToken(tx.Receipts[i].Value)
+	}
+}
+
+// fillBlockResultDecimal - fill the decimal amounts of all transactions in block
+func fillBlockResultDecimal(blk *BlockResult) {
+	for i := range blk.Txs {
+		fillTxResultDecimal(&blk.Txs[i])
+	}
+}
+
+func convertUnit(value, amountUnit string) (result *ConvertUnitResult, err error) {
+
+	var cong string
+	if cong, err = valueToCong(value, amountUnit); err != nil {
+		return
+	}
+
+	if _, err = requireUint(cong); err != nil {
+		return
+	}
+
+	result = new(ConvertUnitResult)
+	result.Cong = cong
+	result.Token = congToToken(cong)
+
+	return
+}
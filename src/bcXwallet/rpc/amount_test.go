@@ -0,0 +1,51 @@
+package rpc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertUnit(t *testing.T) {
+	assert := assert.New(t)
+
+	cases := []struct {
+		value      string
+		amountUnit string
+		cong       string
+		token      string
+	}{
+		{"0", amountUnitCong, "0", "0"},
+		{"1", amountUnitCong, "1", "0.000000001"},
+		{"1000000000", amountUnitCong, "1000000000", "1"},
+		{"1234567890123", "", "1234567890123", "1234.567890123"},
+		{"1.5", amountUnitToken, "1500000000", "1.5"},
+		{"0.000000001", amountUnitToken, "1", "0.000000001"},
+		{"18446744073.709551615", amountUnitToken, "18446744073709551615", "18446744073.709551615"},
+		{"100.", amountUnitToken, "100000000000", "100"},
+	}
+
+	for _, c := range cases {
+		result, err := convertUnit(c.value, c.amountUnit)
+		if assert.Nil(err, c.value) {
+			assert.Equal(c.cong, result.Cong, c.value)
+			assert.Equal(c.token, result.Token, c.value)
+		}
+	}
+}
+
+func TestConvertUnitInvalid(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := convertUnit("0.0000000001", amountUnitToken)
+	assert.NotNil(err)
+
+	_, err = convertUnit("-1", amountUnitToken)
+	assert.NotNil(err)
+
+	_, err = convertUnit("1.5", amountUnitCong)
+	assert.NotNil(err)
+
+	assert.NotNil(checkAmountUnit("wei"))
+	assert.Nil(checkAmountUnit(""))
+}
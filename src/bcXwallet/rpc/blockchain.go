@@ -10,6 +10,7 @@ import (
 	"blockchain/smcsdk/sdk/std"
 	"blockchain/tx2"
 	types3 "blockchain/types"
+	"common/bignumber_v1.0"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
@@ -17,19 +18,29 @@ import (
 	types2 "github.com/tendermint/abci/types"
 	"github.com/tendermint/go-crypto"
 	"github.com/tendermint/tendermint/rpc/core/types"
-	"math/big"
+	tmtypes "github.com/tendermint/tendermint/types"
 	"strings"
+	"time"
 )
 
-var (
-	genesisTokenAddr = ""
+const (
+	maxBlocksOfHeader = 500 // max count of block headers in one bcb_blocks
+	maxBlocksOfDetail = 20  // max count of decoded blocks in one bcb_blocks
+	blockMetasOfRoute = 20  // max count of block metas of node's blockchain route
 )
 
-func blockHeight() (blkHeight *BlockHeightResult, err error) {
+// directions of pending transactions to the addresses they are matched with
+const (
+	pendingIncoming = "incoming"
+	pendingOutgoing = "outgoing"
+	pendingSelf     = "self"
+)
+
+func blockHeight(c *common.Chain) (blkHeight *BlockHeightResult, err error) {
 
 	result := new(core_types.ResultABCIInfo)
 	params := map[string]interface{}{}
-	err = common.DoHttpRequestAndParseEx(common.GetConfig().NodeAddrSlice, "abci_info", params, result)
+	err = common.DoHttpRequestAndParseEx(c.NodeAddrSlice, "abci_info", params, result)
 	if err != nil {
 		return
 	}
@@ -40,75 +51,273 @@ func blockHeight() (blkHeight *BlockHeightResult, err error) {
 	return
 }
 
-func block(height int64) (blk *BlockResult, err error) {
+func status(c *common.Chain) (result *StatusResult, err error) {
+
+	resultStatus := new(core_types.ResultStatus)
+	if err = common.DoHttpRequestAndParseEx(c.NodeAddrSlice, "status", map[string]interface{}{}, resultStatus); err != nil {
+		return
+	}
+
+	health := new(core_types.ResultHealth)
+	if err = common.DoHttpRequestAndParseEx(c.NodeAddrSlice, "health", map[string]interface{}{}, health); err != nil {
+		return
+	}
+
+	result = &StatusResult{
+		ChainID:         health.ChainID,
+		ChainVersion:    health.ChainVersion,
+		LatestHeight:    resultStatus.SyncInfo.LatestBlockHeight,
+		LatestBlockHash: "0x" + hex.EncodeToString(resultStatus.SyncInfo.LatestBlockHash),
+		LatestBlockTime: resultStatus.SyncInfo.LatestBlockTime.String(),
+		CatchingUp:      resultStatus.SyncInfo.Syncing,
+		NodeVersion:     resultStatus.NodeInfo.Version,
+	}
+
+	return
+}
+
+// chainVersion - the last known chain version is returned if nodes cannot be queried now
+func chainVersion(c *common.Chain) (result *ChainVersionResult, err error) {
+
+	status, err := c.RefreshChainVersion()
+	if err != nil {
+		if status.ChainVersion == "" {
+			return
+		}
+		common.GetLogger().Error("Cannot refresh chain version", "error", err)
+	}
+
+	result = &ChainVersionResult{
+		ChainVersion:  status.ChainVersion,
+		Source:        status.Source,
+		Height:        status.Height,
+		RefreshedTime: status.RefreshedTime,
+		ConfigVersion: status.ConfigVersion,
+	}
+
+	return result, nil
+}
+
+func validators(c *common.Chain, height int64) (result *ValidatorsResult, err error) {
+
+	resultValidators := new(core_types.ResultValidators)
+	params := map[string]interface{}{"height": height}
+	if err = common.DoHttpRequestAndParseEx(c.NodeAddrSlice, "validators", params, resultValidators); err != nil {
+		return
+	}
+
+	result = &ValidatorsResult{
+		Height:     resultValidators.BlockHeight,
+		Validators: make([]ValidatorResult, 0, len(resultValidators.Validators)),
+	}
+	for _, val := range resultValidators.Validators {
+		pubKey := ""
+		if pk, ok := val.PubKey.(crypto.PubKeyEd25519); ok {
+			pubKey = "0x" + hex.EncodeToString(pk[:])
+		} else if val.PubKey != nil {
+			pubKey = "0x" + hex.EncodeToString(val.PubKey.Bytes())
+		}
+
+		result.Validators = append(result.Validators,
+			ValidatorResult{
+				Address:    val.Address,
+				PubKey:     pubKey,
+				Power:      val.VotingPower,
+				RewardAddr: val.RewardAddr,
+				Name:       val.Name})
+	}
+
+	return
+}
+
+func netInfo(c *common.Chain) (result *NetInfoResult, err error) {
+
+	resultNetInfo := new(core_types.ResultNetInfo)
+	if err = common.DoHttpRequestAndParseEx(c.NodeAddrSlice, "net_info", map[string]interface{}{}, resultNetInfo); err != nil {
+		return
+	}
+
+	result = &NetInfoResult{
+		Listening: resultNetInfo.Listening,
+		PeerCount: len(resultNetInfo.Peers),
+		Peers:     make([]PeerResult, 0, len(resultNetInfo.Peers)),
+	}
+	for _, peer := range resultNetInfo.Peers {
+		result.Peers = append(result.Peers,
+			PeerResult{
+				ID:         string(peer.ID),
+				Moniker:    peer.Moniker,
+				ListenAddr: peer.ListenAddr,
+				Version:    peer.Version,
+				IsOutbound: peer.IsOutbound})
+	}
+
+	return
+}
+
+func block(c *common.Chain, height int64) (blk *BlockResult, err error) {
 
 	result := new(core_types.ResultBlock)
 	params := map[string]interface{}{"height": height}
-	err = common.DoHttpRequestAndParseEx(common.GetConfig().NodeAddrSlice, "block", params, result)
+	err = common.DoHttpRequestAndParseEx(c.NodeAddrSlice, "block", params, result)
 	if err != nil {
 		return
 	}
 
-	blk = new(BlockResult)
-	blk.BlockHeight = result.BlockMeta.Header.Height
-	blk.BlockHash = "0x" + hex.EncodeToString(result.BlockMeta.BlockID.Hash)
-	blk.ParentHash = "0x" + hex.EncodeToString(result.BlockMeta.Header.LastBlockID.Hash)
-	blk.ChainID = result.BlockMeta.Header.ChainID
-	blk.ValidatorHash = "0x" + hex.EncodeToString(result.BlockMeta.Header.ValidatorsHash)
-	blk.ConsensusHash = "0x" + hex.EncodeToString(result.BlockMeta.Header.ConsensusHash)
-	blk.BlockTime = result.BlockMeta.Header.Time.String()
+	c.ObserveChainVersion(result.Block.Height, result.Block.ChainVersion)
+
+	blk = blockOfMeta(result.BlockMeta)
 	blk.BlockSize = result.BlockSize
-	blk.ProposerAddress = result.BlockMeta.Header.ProposerAddress
 
-	blk.Txs = make([]TxResult, 0)
+	blk.Txs, err = blockTxs(c, result)
+
+	return
+}
+
+// blockTxs - decode all transactions of block with results of block, without query every transaction
+func blockTxs(c *common.Chain, resultBlock *core_types.ResultBlock) (txs []TxResult, err error) {
+
+	txs = make([]TxResult, 0)
 	var blkResults *core_types.ResultBlockResults
-	if blkResults, err = blockResults(height); err != nil {
+	if blkResults, err = blockResults(c, resultBlock.Block.Height); err != nil {
 		return
 	}
 
-	for _, blkResult := range blkResults.Results.DeliverTx {
+	for k, deliverTx := range blkResults.Results.DeliverTx {
+		if k >= len(resultBlock.Block.Txs) {
+			return nil, errors.New("block results do not match block")
+		}
+
 		var tx *TxResult
-		if tx, err = transaction(hex.EncodeToString(blkResult.TxHash), result); err != nil {
+		if tx, err = txResult(c, hex.EncodeToString(deliverTx.TxHash), string(resultBlock.Block.Txs[k]), *deliverTx, resultBlock); err != nil {
 			return
 		}
-		blk.Txs = append(blk.Txs, *tx)
+		txs = append(txs, *tx)
+	}
+
+	return
+}
+
+// blockOfMeta - block result with header of block meta only
+func blockOfMeta(meta *tmtypes.BlockMeta) *BlockResult {
+	blk := new(BlockResult)
+	blk.BlockHeight = meta.Header.Height
+	blk.BlockHash = "0x" + hex.EncodeToString(meta.BlockID.Hash)
+	blk.ParentHash = "0x" + hex.EncodeToString(meta.Header.LastBlockID.Hash)
+	blk.ChainID = meta.Header.ChainID
+	blk.ValidatorHash = "0x" + hex.EncodeToString(meta.Header.ValidatorsHash)
+	blk.ConsensusHash = "0x" + hex.EncodeToString(meta.Header.ConsensusHash)
+	blk.BlockTime = meta.Header.Time.String()
+	blk.ProposerAddress = meta.Header.ProposerAddress
+	blk.Txs = make([]TxResult, 0)
+
+	return blk
+}
+
+// blockMetas - block metas from minHeight to maxHeight in ascending order with node's blockchain route
+func blockMetas(c *common.Chain, minHeight, maxHeight int64) (metas []*tmtypes.BlockMeta, err error) {
+
+	metas = make([]*tmtypes.BlockMeta, 0, maxHeight-minHeight+1)
+	for low := minHeight; low <= maxHeight; low += blockMetasOfRoute {
+		high := low + blockMetasOfRoute - 1
+		if high > maxHeight {
+			high = maxHeight
+		}
+
+		info := new(core_types.ResultBlockchainInfo)
+		params := map[string]interface{}{"minHeight": low, "maxHeight": high}
+		if err = common.DoHttpRequestAndParseEx(c.NodeAddrSlice, "blockchain", params, info); err != nil {
+			return nil, err
+		}
+
+		// block metas of route are in descending order
+		for i := len(info.BlockMetas) - 1; i >= 0; i-- {
+			meta := info.BlockMetas[i]
+			if meta == nil || meta.Header == nil || meta.Header.Height < low || meta.Header.Height > high {
+				continue
+			}
+			metas = append(metas, meta)
+		}
+	}
+
+	return
+}
+
+// blocks - headers of blocks from node's blockchain route, with detail only blocks that have
+// transactions are fetched one by one, empty blocks are made of their headers
+func blocks(c *common.Chain, minHeight, maxHeight, lastHeight int64, detail bool) (result *BlocksResult, err error) {
+
+	limit := int64(maxBlocksOfHeader)
+	if detail {
+		limit = maxBlocksOfDetail
+	}
+
+	result = new(BlocksResult)
+	result.LastHeight = lastHeight
+	if maxHeight-minHeight+1 > limit {
+		maxHeight = minHeight + limit - 1
+		result.NextHeight = maxHeight + 1
+	}
+
+	var metas []*tmtypes.BlockMeta
+	if metas, err = blockMetas(c, minHeight, maxHeight); err != nil {
+		return nil, err
+	}
+
+	if detail {
+		result.Blocks = make([]BlockResult, 0, len(metas))
+		for _, meta := range metas {
+			blk := blockOfMeta(meta)
+			if meta.Header.NumTxs > 0 {
+				if blk, err = block(c, meta.Header.Height); err != nil {
+					return nil, err
+				}
+			} else {
+				c.ObserveChainVersion(meta.Header.Height, meta.Header.ChainVersion)
+			}
+			result.Blocks = append(result.Blocks, *blk)
+		}
+
+		return
+	}
+
+	result.Result = make([]SimpleBlockResult, 0, len(metas))
+	for _, meta := range metas {
+		result.Result = append(result.Result, SimpleBlockResult{
+			BlockHeight: meta.Header.Height,
+			BlockHash:   "0x" + hex.EncodeToString(meta.BlockID.Hash),
+			BlockTime:   meta.Header.Time.String(),
+			TxCount:     meta.Header.NumTxs,
+		})
 	}
 
 	return
 }
 
-func transaction(txHash string, resultBlock *core_types.ResultBlock) (tx *TxResult, err error) {
+func transaction(c *common.Chain, txHash string, resultBlock *core_types.ResultBlock) (tx *TxResult, err error) {
 
 	if txHash[:2] == "0x" {
 		txHash = txHash[2:]
 	}
 	result := new(core_types.ResultTx)
-	params := map[string]interface{}{"hash": txHash}
-	err = common.DoHttpRequestAndParseEx(common.GetConfig().NodeAddrSlice, "tx", params, result)
+	err = common.DoQuorumTx(c.NodeAddrSlice, txHash, result)
 	if err != nil {
 		return
 	}
 
 	if resultBlock == nil {
 		resultBlock = new(core_types.ResultBlock)
-		params = map[string]interface{}{"height": result.Height}
-		err = common.DoHttpRequestAndParseEx(common.GetConfig().NodeAddrSlice, "block", params, resultBlock)
+		params := map[string]interface{}{"height": result.Height}
+		err = common.DoHttpRequestAndParseEx(c.NodeAddrSlice, "block", params, resultBlock)
 		if err != nil {
 			return
 		}
 	}
 
-	//ParseTX
-	var transaction tx3.Transaction
-	var fromAddr string
-	var msg Message
-	var GasLimit uint64
-	var Nonce uint64
-	var Note string
 	var txStr string
 
 	var blkResults *core_types.ResultBlockResults
-	if blkResults, err = blockResults(result.Height); err != nil {
+	if blkResults, err = blockResults(c, result.Height); err != nil {
 		return
 	}
 
@@ -122,16 +331,51 @@ func transaction(txHash string, resultBlock *core_types.ResultBlock) (tx *TxResu
 		}
 	}
 
+	return txResult(c, txHash, txStr, result.DeliverResult, resultBlock)
+}
+
+// txResult - decode transaction and its result in block
+func txResult(c *common.Chain, txHash, txStr string, deliverTx types2.ResponseDeliverTx, resultBlock *core_types.ResultBlock) (tx *TxResult, err error) {
+
+	tx, err = parseTx(c, txStr, resultBlock.Block.Height, resultBlock.Block.ChainVersion)
+	if err != nil {
+		return
+	}
+
+	tx.TxHash = "0x" + txHash
+	tx.TxTime = resultBlock.BlockMeta.Header.Time.String()
+	tx.Code = deliverTx.Code
+	tx.Log = deliverTx.Log
+	tx.BlockHash = "0x" + hex.EncodeToString(resultBlock.BlockMeta.BlockID.Hash)
+	tx.BlockHeight = resultBlock.BlockMeta.Header.Height
+	tx.Fee = deliverTx.Fee
+	tx.Receipts = receipts(deliverTx.Tags)
+
+	return
+}
+
+// parseTx - sender, nonce, gas limit, note and messages of v1 or v2 transaction, contracts of messages are
+// resolved at height
+func parseTx(c *common.Chain, txStr string, height int64, chainVersion *int64) (tx *TxResult, err error) {
+
+	//ParseTX
+	var transaction tx3.Transaction
+	var fromAddr string
+	var msg Message
+	var GasLimit uint64
+	var Nonce uint64
+	var Note string
+
 	messages := make([]Message, 0)
 
 	splitTx := strings.Split(txStr, ".")
-	if splitTx[1] == "v1" {
+	if len(splitTx) > 1 && splitTx[1] == "v1" {
 		// parse transaction V1
-		fromAddr, _, err = transaction.TxParse(crypto.GetChainId(), txStr)
+		fromAddr, _, err = transaction.TxParse(c.ChainID, txStr)
 		if err != nil {
 			return
 		}
-		msg, err = message(transaction)
+		msg, err = message(c, transaction)
 		if err != nil {
 			return
 		}
@@ -140,20 +384,20 @@ func transaction(txHash string, resultBlock *core_types.ResultBlock) (tx *TxResu
 		Nonce = transaction.Nonce
 		Note = transaction.Note
 
-	} else if splitTx[1] == "v2" {
+	} else if len(splitTx) > 1 && splitTx[1] == "v2" {
 		// parse transaction V2
 		var txv2 types3.Transaction
 		var pubKey crypto.PubKeyEd25519
-		txv2, pubKey, err = tx2.TxParse(txStr)
+		txv2, pubKey, err = tx2.TxParseOfChain(c.ChainID, txStr)
 		if err != nil {
 			return
 		}
 
-		fromAddr = pubKey.Address(crypto.GetChainId())
+		fromAddr = pubKey.Address(c.ChainID)
 
 		var msg Message
 		for i := 0; i < len(txv2.Messages); i++ {
-			msg, err = messageV2(resultBlock.Block.ChainID, txv2.Messages[i], resultBlock.Block.Height, resultBlock.Block.ChainVersion)
+			msg, err = messageV2(c, txv2.Messages[i], height, chainVersion)
 			if err != nil {
 				return
 			}
@@ -168,32 +412,24 @@ func transaction(txHash string, resultBlock *core_types.ResultBlock) (tx *TxResu
 	}
 
 	tx = new(TxResult)
-	tx.TxHash = "0x" + txHash
-	tx.TxTime = resultBlock.BlockMeta.Header.Time.String()
-	tx.Code = result.DeliverResult.Code
-	tx.Log = result.DeliverResult.Log
-	tx.BlockHash = "0x" + hex.EncodeToString(resultBlock.BlockMeta.BlockID.Hash)
-	tx.BlockHeight = resultBlock.BlockMeta.Header.Height
 	tx.From = fromAddr
 	tx.Nonce = Nonce
 	tx.GasLimit = GasLimit
-	tx.Fee = result.DeliverResult.Fee
 	tx.Note = Note
-
-	tx.Messages = make([]Message, 0)
 	tx.Messages = messages
 
 	return
 }
 
-func messageV2(chainID string, message types3.Message, height int64, chainVersion *int64) (msg Message, err error) {
+func messageV2(c *common.Chain, message types3.Message, height int64, chainVersion *int64) (msg Message, err error) {
 
 	methodID := fmt.Sprintf("%x", message.MethodID)
 
 	msg.SmcAddress = message.Contract
-	if msg.SmcName, msg.Method, err = contractNameAndMethodV2(message.Contract, chainID, methodID, height, chainVersion); err != nil {
+	if msg.SmcName, msg.Method, err = contractNameAndMethodV2(c, message.Contract, methodID, height, chainVersion); err != nil {
 		return
 	}
+	msg.Params = decodeParamsV2(msg.Method, message.Items)
 
 	if methodID == transferMethodIDV2 {
 		if len(message.Items) != 2 {
@@ -216,33 +452,15 @@ func messageV2(chainID string, message types3.Message, height int64, chainVersio
 	return
 }
 
-func contractNameAndMethodV2(contractAddress types3.Address, chainID, methodID string, height int64, chainVersion *int64) (contractName string, method string, err error) {
-
-	cfg := common.GetConfig()
-	addrS := cfg.NodeAddrSlice
+func contractNameAndMethodV2(c *common.Chain, contractAddress types3.Address, methodID string, height int64, chainVersion *int64) (contractName string, method string, err error) {
 
-	contract := new(std.Contract)
-	if err = common.DoHttpQueryAndParse(addrS, std.KeyOfContract(contractAddress), contract); err != nil {
+	contract, err := contractV2(c, contractAddress, height)
+	if err != nil {
 		return
 	}
 
 	if chainVersion != nil && contract.LoseHeight != 0 && contract.LoseHeight < height {
-		conVer := new(std.ContractVersionList)
-		if err = common.DoHttpQueryAndParse(addrS, std.KeyOfContractsWithName(contract.OrgID, contract.Name), conVer); err == nil {
-			for index, eh := range conVer.EffectHeights {
-				if eh <= height {
-					tmp := new(std.Contract)
-					if err = common.DoHttpQueryAndParse(addrS, std.KeyOfContract(conVer.ContractAddrList[index]), tmp); err == nil {
-						if tmp.LoseHeight == 0 || (tmp.LoseHeight != 0 && tmp.LoseHeight > height) {
-							contract = tmp
-							break
-						}
-					} else {
-						return
-					}
-				}
-			}
-		} else {
+		if contract, err = effectiveContractV2(c, contract, height); err != nil {
 			return
 		}
 	}
@@ -257,17 +475,38 @@ func contractNameAndMethodV2(contractAddress types3.Address, chainID, methodID s
 	return contract.Name, method, nil
 }
 
-func balance(address keys.Address) (result *BalanceResult, err error) {
+// effectiveContractV2 - version of contract that is effective at height, contract itself if no version is
+func effectiveContractV2(c *common.Chain, contract *std.Contract, height int64) (*std.Contract, error) {
+	conVer, err := contractVersionsV2(c, contract.OrgID, contract.Name, height)
+	if err != nil {
+		return nil, err
+	}
+	for index, eh := range conVer.EffectHeights {
+		if eh <= height {
+			tmp, err := contractV2(c, conVer.ContractAddrList[index], height)
+			if err != nil {
+				return nil, err
+			}
+			if tmp.LoseHeight == 0 || tmp.LoseHeight > height {
+				return tmp, nil
+			}
+		}
+	}
+
+	return contract, nil
+}
+
+func balance(c *common.Chain, address keys.Address, height int64) (result *BalanceResult, err error) {
 
-	return balanceOfToken(address, genesisToken(), "")
+	return balanceOfToken(c, address, genesisToken(c), "", height)
 }
 
-func balanceOfToken(address, tokenAddress keys.Address, tokenName string) (result *BalanceResult, err error) {
+func balanceOfToken(c *common.Chain, address, tokenAddress keys.Address, tokenName string, height int64) (result *BalanceResult, err error) {
 
 	var value []byte
 	if tokenName != "" {
 		var tmpAddress keys.Address
-		if value, err = common.DoHttpQuery(common.GetConfig().NodeAddrSlice, keyOfTokenName(tokenName)); err != nil {
+		if value, err = common.DoHttpQuery(c, keyOfTokenName(tokenName)); err != nil {
 			return
 		}
 		if len(value) == 0 {
@@ -286,7 +525,7 @@ func balanceOfToken(address, tokenAddress keys.Address, tokenName string) (resul
 		return nil, errors.New("tokenAddress and tokenName cannot be empty with both")
 	}
 
-	if value, err = common.DoHttpQuery(common.GetConfig().NodeAddrSlice, keyOfAccountToken(address, tokenAddress)); err != nil {
+	if value, err = common.DoQuorumQuery(c, keyOfAccountToken(address, tokenAddress), height); err != nil {
 		return
 	}
 	result = new(BalanceResult)
@@ -303,26 +542,35 @@ func balanceOfToken(address, tokenAddress keys.Address, tokenName string) (resul
 	return
 }
 
-func allBalance(address keys.Address) (items *[]AllBalanceItemResult, err error) {
+func allBalance(c *common.Chain, address keys.Address, height int64) (items *[]AllBalanceItemResult, err error) {
 
-	tokens := make([]string, 0)
-	if err = common.DoHttpQueryAndParse(common.GetConfig().NodeAddrSlice, keyOfAccount(address), &tokens); err != nil {
+	value, err := common.DoQuorumQuery(c, keyOfAccount(address), height)
+	if err != nil {
 		return
 	}
 
+	// account that never received any token has no state, its balances are all zero
 	balanceItems := make([]AllBalanceItemResult, 0)
+	if len(value) == 0 {
+		return &balanceItems, nil
+	}
+	tokens := make([]string, 0)
+	if err = json.Unmarshal(value, &tokens); err != nil {
+		return
+	}
+
 	for _, token := range tokens {
 		splitToken := strings.Split(token, "/")
 		if splitToken[4] != "token" || len(splitToken) != 6 {
 			continue
 		}
 		tokenBalance := new(types.TokenBalance)
-		if err = common.DoHttpQueryAndParse(common.GetConfig().NodeAddrSlice, token, tokenBalance); err != nil {
+		if err = common.DoQuorumQueryAndParse(c, token, height, tokenBalance); err != nil {
 			return
 		}
 
 		var name string
-		if name, err = tokenName(tokenBalance.Address); err != nil {
+		if name, err = tokenName(c, tokenBalance.Address); err != nil {
 			return
 		}
 
@@ -336,14 +584,14 @@ func allBalance(address keys.Address) (items *[]AllBalanceItemResult, err error)
 	return &balanceItems, err
 }
 
-func nonce(acctAddress keys.Address) (result *NonceResult, err error) {
+func nonce(c *common.Chain, acctAddress keys.Address, height int64) (result *NonceResult, err error) {
 
 	type account struct {
 		Nonce uint64 `json:"nonce"`
 	}
 
 	a := new(account)
-	value, err := common.DoHttpQuery(common.GetConfig().NodeAddrSlice, keyOfAccountNonce(acctAddress))
+	value, err := common.DoQuorumQuery(c, keyOfAccountNonce(acctAddress), height)
 	if err != nil {
 		return
 	}
@@ -363,14 +611,20 @@ func nonce(acctAddress keys.Address) (result *NonceResult, err error) {
 	return
 }
 
-func commitTx(tx string) (commit *CommitTxResult, err error) {
+func commitTx(c *common.Chain, tx string) (commit *CommitTxResult, err error) {
 
 	var result *types.ResultBroadcastTxCommit
-	result, err = common.DoHttpRequestAndParse(common.GetConfig().NodeAddrSlice, tx)
+	result, err = common.DoHttpRequestAndParse(c.NodeAddrSlice, tx)
 	if err != nil {
 		return
 	}
 
+	return commitResultOf(result), nil
+}
+
+// commitResultOf - result of CheckTx if it failed, otherwise result of DeliverTx
+func commitResultOf(result *types.ResultBroadcastTxCommit) (commit *CommitTxResult) {
+
 	commit = new(CommitTxResult)
 	if result.CheckTx.Code != types2.CodeTypeOK {
 		commit.Code = result.CheckTx.Code
@@ -386,11 +640,91 @@ func commitTx(tx string) (commit *CommitTxResult, err error) {
 	return
 }
 
-func blockResults(height int64) (blkResults *core_types.ResultBlockResults, err error) {
+func pendingTxs(c *common.Chain, address string) (result *PendingTxsResult, err error) {
+
+	addresses := make(map[string]bool)
+	wallets, err := walletAddresses(c)
+	if err != nil {
+		return
+	}
+	if address == "" {
+		for _, walletAddress := range wallets {
+			addresses[walletAddress] = true
+		}
+	} else if walletAddress, ok := wallets[address]; ok {
+		addresses[walletAddress] = true
+	} else {
+		addresses[address] = true
+	}
+
+	unconfirmed := new(core_types.ResultUnconfirmedTxs)
+	err = common.DoHttpRequestAndParseEx(c.NodeAddrSlice, "unconfirmed_txs", map[string]interface{}{}, unconfirmed)
+	if err != nil {
+		return
+	}
+
+	// contracts of messages are resolved with the latest state since pending transactions are not in any block
+	chain, err := status(c)
+	if err != nil {
+		return
+	}
+	var chainVersion *int64
+	if chain.ChainVersion != 0 {
+		chainVersion = &chain.ChainVersion
+	}
+
+	txs := matchPendingTxs(c, unconfirmed.Txs, addresses, chain.LatestHeight, chainVersion)
+
+	return &PendingTxsResult{Total: unconfirmed.N, Count: len(txs), Txs: txs}, nil
+}
+
+// matchPendingTxs - decoded transactions sent from or to addresses, the ones cannot be decoded are skipped
+func matchPendingTxs(c *common.Chain, txs []tmtypes.Tx, addresses map[string]bool, height int64, chainVersion *int64) []PendingTxResult {
+	results := make([]PendingTxResult, 0)
+
+	for _, txBytes := range txs {
+		tx, err := parseTx(c, string(txBytes), height, chainVersion)
+		if err != nil {
+			continue
+		}
+
+		outgoing := addresses[tx.From]
+		incoming := false
+		for _, msg := range tx.Messages {
+			if addresses[msg.To] {
+				incoming = true
+			}
+		}
+		if !outgoing && !incoming {
+			continue
+		}
+
+		direction := pendingIncoming
+		if outgoing && incoming {
+			direction = pendingSelf
+		} else if outgoing {
+			direction = pendingOutgoing
+		}
+
+		results = append(results,
+			PendingTxResult{
+				TxHash:    "0x" + hex.EncodeToString(txBytes.Hash()),
+				From:      tx.From,
+				Nonce:     tx.Nonce,
+				GasLimit:  tx.GasLimit,
+				Note:      tx.Note,
+				Messages:  tx.Messages,
+				Direction: direction})
+	}
+
+	return results
+}
+
+func blockResults(c *common.Chain, height int64) (blkResults *core_types.ResultBlockResults, err error) {
 
 	blkResults = new(core_types.ResultBlockResults)
 	params := map[string]interface{}{"height": height}
-	err = common.DoHttpRequestAndParseEx(common.GetConfig().NodeAddrSlice, "block_results", params, blkResults)
+	err = common.DoHttpRequestAndParseEx(c.NodeAddrSlice, "block_results", params, blkResults)
 	if err != nil {
 		return
 	}
@@ -398,7 +732,7 @@ func blockResults(height int64) (blkResults *core_types.ResultBlockResults, err
 	return
 }
 
-func message(transation tx3.Transaction) (msg Message, err error) {
+func message(c *common.Chain, transation tx3.Transaction) (msg Message, err error) {
 
 	var methodInfo tx3.MethodInfo
 	if err = rlp.DecodeBytes(transation.Data, &methodInfo); err != nil {
@@ -407,26 +741,31 @@ func message(transation tx3.Transaction) (msg Message, err error) {
 	methodID := fmt.Sprintf("%x", methodInfo.MethodID)
 
 	msg.SmcAddress = transation.To
-	if msg.SmcName, msg.Method, err = contractNameAndMethod(transation.To, methodID); err != nil {
+	if msg.SmcName, msg.Method, err = contractNameAndMethod(c, transation.To, methodID); err != nil {
 		return
 	}
 
+	var params = make([][]byte, 0)
+	if rlp.DecodeBytes(methodInfo.ParamData, &params) == nil {
+		msg.Params = decodeParamsV1(msg.Method, params)
+	}
+
 	if methodID == transferMethodIDV1 {
 		var itemsBytes = make([][]byte, 0)
 		if err = rlp.DecodeBytes(methodInfo.ParamData, &itemsBytes); err != nil {
 			return
 		}
 		msg.To = string(itemsBytes[0])
-		msg.Value = new(big.Int).SetBytes(itemsBytes[1][:]).String()
+		msg.Value = bignumber.SetBytes(itemsBytes[1]).String()
 	}
 
 	return
 }
 
-func contractNameAndMethod(contractAddress keys.Address, methodID string) (contractName string, method string, err error) {
+func contractNameAndMethod(c *common.Chain, contractAddress keys.Address, methodID string) (contractName string, method string, err error) {
 
-	contract := new(types.Contract)
-	if err = common.DoHttpQueryAndParse(common.GetConfig().NodeAddrSlice, keyOfContract(contractAddress), contract); err != nil {
+	contract, err := contractV1(c, contractAddress)
+	if err != nil {
 		return
 	}
 
@@ -440,27 +779,205 @@ func contractNameAndMethod(contractAddress keys.Address, methodID string) (contr
 	return contract.Name, method, nil
 }
 
-func tokenName(tokenAddress keys.Address) (name string, err error) {
-
-	token := new(types.IssueToken)
+func tokenName(c *common.Chain, tokenAddress keys.Address) (name string, err error) {
 
-	if err = common.DoHttpQueryAndParse(common.GetConfig().NodeAddrSlice, keyOfToken(tokenAddress), token); err != nil {
+	token, err := tokenOf(c, tokenAddress)
+	if err != nil {
 		return
 	}
 
 	return token.Name, err
 }
 
-func genesisToken() string {
-	if genesisTokenAddr == "" {
-		token := new(types.IssueToken)
+func genesisToken(c *common.Chain) string {
+	token, err := genesisTokenOf(c)
+	if err != nil {
+		return ""
+	}
+
+	return token.Address
+}
 
-		if err := common.DoHttpQueryAndParse(common.GetConfig().NodeAddrSlice, keyOfGenesisToken(), token); err != nil {
-			return ""
+func tokens(c *common.Chain) (result *TokensResult, err error) {
+
+	addresses := make([]keys.Address, 0)
+	if err = common.DoHttpQueryAndParse(c, keyOfAllToken(), &addresses); err != nil {
+		return
+	}
+
+	height, err := blockHeight(c)
+	if err != nil {
+		return
+	}
+
+	result = &TokensResult{Tokens: make([]TokenInfoResult, 0, len(addresses))}
+	for _, address := range addresses {
+		var info *TokenInfoResult
+		if info, err = tokenInfoOf(c, address, height.LastBlock); err != nil {
+			return
+		}
+		result.Tokens = append(result.Tokens, *info)
+	}
+
+	return
+}
+
+func tokenInfo(c *common.Chain, tokenAddress keys.Address, tokenName, tokenSymbol string) (result *TokenInfoResult, err error) {
+
+	key := ""
+	if tokenName != "" {
+		key = keyOfTokenName(tokenName)
+	} else if tokenSymbol != "" {
+		key = keyOfTokenSymbol(tokenSymbol)
+	}
+	if key != "" {
+		var value []byte
+		if value, err = common.DoHttpQuery(c, key); err != nil {
+			return
+		}
+		if len(value) == 0 {
+			return nil, errors.New("invalid tokenName or tokenSymbol")
+		}
+		if err = json.Unmarshal(value, &tokenAddress); err != nil {
+			return
+		}
+	}
+
+	height, err := blockHeight(c)
+	if err != nil {
+		return
+	}
+
+	return tokenInfoOf(c, tokenAddress, height.LastBlock)
+}
+
+// tokenInfoOf - token of address with its contract effective at height, the supply of token may change,
+// so it's not cached
+func tokenInfoOf(c *common.Chain, tokenAddress keys.Address, height int64) (result *TokenInfoResult, err error) {
+
+	value, err := common.DoHttpQuery(c, keyOfToken(tokenAddress))
+	if err != nil {
+		return
+	}
+	if len(value) == 0 {
+		return nil, errors.New("invalid tokenAddress")
+	}
+
+	token := new(std.Token)
+	if err = json.Unmarshal(value, token); err != nil {
+		return
+	}
+
+	result = &TokenInfoResult{
+		Address:          token.Address,
+		Owner:            token.Owner,
+		Name:             token.Name,
+		Symbol:           token.Symbol,
+		TotalSupply:      token.TotalSupply.String(),
+		AddSupplyEnabled: token.AddSupplyEnabled,
+		BurnEnabled:      token.BurnEnabled,
+		GasPrice:         token.GasPrice,
+	}
+
+	// contract of token is never upgraded in chain version 1
+	chainVersion, err := c.ChainVersion()
+	if err != nil {
+		return
+	}
+	if chainVersion == "1" {
+		var contract *types.Contract
+		if contract, err = contractV1(c, tokenAddress); err != nil {
+			return
+		}
+		result.ContractAddress = contract.Address
+		result.ContractVersion = contract.Version
+
+		return
+	}
+
+	contract, err := contractV2(c, tokenAddress, height)
+	if err != nil {
+		return
+	}
+	if contract.LoseHeight != 0 && contract.LoseHeight <= height {
+		if contract, err = effectiveContractV2(c, contract, height); err != nil {
+			return
+		}
+	}
+	result.ContractAddress = contract.Address
+	result.ContractVersion = contract.Version
+
+	return
+}
+
+// blockTime - time of block at height
+func blockTime(c *common.Chain, height int64) (blkTime time.Time, err error) {
+
+	info := new(core_types.ResultBlockchainInfo)
+	params := map[string]interface{}{"minHeight": height, "maxHeight": height}
+	if err = common.DoHttpRequestAndParseEx(c.NodeAddrSlice, "blockchain", params, info); err != nil {
+		return
+	}
+
+	for _, meta := range info.BlockMetas {
+		if meta != nil && meta.Header != nil && meta.Header.Height == height {
+			return meta.Header.Time, nil
+		}
+	}
+
+	return blkTime, fmt.Errorf("no block at height %d", height)
+}
+
+// heightAtTime - height of the last block that is not later than timestamp
+func heightAtTime(c *common.Chain, timestamp int64) (height int64, blkTime time.Time, err error) {
+
+	blkHeight, err := blockHeight(c)
+	if err != nil {
+		return
+	}
+
+	// binary search in [low, high], block of low is never later than timestamp
+	low, high := int64(1), blkHeight.LastBlock
+	if blkTime, err = blockTime(c, low); err != nil {
+		return
+	}
+	if blkTime.Unix() > timestamp {
+		return 0, blkTime, errors.New("Timestamp is earlier than the first block ")
+	}
+	for low < high {
+		middle := (low + high + 1) / 2
+		var middleTime time.Time
+		if middleTime, err = blockTime(c, middle); err != nil {
+			return
+		}
+		if middleTime.Unix() <= timestamp {
+			low = middle
 		} else {
-			genesisTokenAddr = token.Address
+			high = middle - 1
 		}
 	}
 
-	return genesisTokenAddr
+	if blkTime, err = blockTime(c, low); err != nil {
+		return
+	}
+
+	return low, blkTime, nil
+}
+
+func balanceAtTime(c *common.Chain, address, tokenAddress keys.Address, timestamp int64) (result *BalanceAtTimeResult, err error) {
+
+	height, blkTime, err := heightAtTime(c, timestamp)
+	if err != nil {
+		return
+	}
+
+	if tokenAddress == "" {
+		tokenAddress = genesisToken(c)
+	}
+	balanceResult, err := balanceOfToken(c, address, tokenAddress, "", height)
+	if err != nil {
+		return
+	}
+
+	return &BalanceAtTimeResult{Height: height, BlockTime: blkTime.String(), Balance: balanceResult.Balance}, nil
 }
@@ -0,0 +1,93 @@
+package rpc
+
+import (
+	"bcXwallet/common"
+	"bcXwallet/common/config"
+	"blockchain/smcsdk/sdk/bn"
+	"blockchain/smcsdk/sdk/std"
+	"blockchain/tx2"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tendermint/go-crypto"
+	tmtypes "github.com/tendermint/tendermint/types"
+)
+
+func TestMatchPendingTxs(t *testing.T) {
+	assert := assert.New(t)
+	tx2.Init(testChainID)
+	crypto.SetChainId(testChainID)
+	privKeyBytes, _ := hex.DecodeString(testPrivKey[2:])
+	sender := crypto.PrivKeyEd25519FromBytes(privKeyBytes).PubKey().Address(testChainID)
+
+	token := &std.Contract{Address: "localToken", Name: "token-basic", Version: "1.0", EffectHeight: 1,
+		Methods: []std.Method{{MethodID: transferMethodIDV2, ProtoType: "Transfer(types.Address,bn.Number)"}}}
+	metadata.set(metaKey(testChain, metaContractV2, std.KeyOfContract(token.Address)), token, heightOfImmutable)
+	defer metadata.remove(metaKey(testChain, metaContractV2, std.KeyOfContract(token.Address)))
+
+	outgoing := GenerateTx(testChainID, token.Address, 0x44D8CA60, []interface{}{"bcbOther", bn.N(100)}, 3, 500, "out", testPrivKey)
+	self := GenerateTx(testChainID, token.Address, 0x44D8CA60, []interface{}{sender, bn.N(200)}, 4, 500, "self", testPrivKey)
+	txs := []tmtypes.Tx{tmtypes.Tx(outgoing), tmtypes.Tx("garbage"), tmtypes.Tx(self)}
+
+	results := matchPendingTxs(testChain, txs, map[string]bool{sender: true}, 10, nil)
+	if assert.Equal(2, len(results)) {
+		assert.Equal(pendingOutgoing, results[0].Direction)
+		assert.Equal(uint64(3), results[0].Nonce)
+		assert.Equal("bcbOther", results[0].Messages[0].To)
+		assert.Equal("100", results[0].Messages[0].Value)
+		assert.Equal(pendingSelf, results[1].Direction)
+		assert.Equal(sender, results[1].From)
+	}
+
+	// the receiver sees the transfer as incoming
+	results = matchPendingTxs(testChain, txs, map[string]bool{"bcbOther": true}, 10, nil)
+	if assert.Equal(1, len(results)) {
+		assert.Equal(pendingIncoming, results[0].Direction)
+		assert.Equal("0x"+hex.EncodeToString(tmtypes.Tx(outgoing).Hash()), results[0].TxHash)
+	}
+
+	assert.Equal(0, len(matchPendingTxs(testChain, txs, map[string]bool{"bcbNone": true}, 10, nil)))
+}
+
+func TestBlocks(t *testing.T) {
+	assert := assert.New(t)
+
+	// the node has no block with transactions, so only the blockchain route is called
+	var blockCalls int32
+	node := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string `json:"method"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Method == "block" {
+			atomic.AddInt32(&blockCalls, 1)
+		}
+		w.Write([]byte(`{"jsonrpc":"2.0","id":"jsonrpc-client","result":{"last_height":12,"block_metas":[` +
+			`{"block_id":{"hash":"0B"},"header":{"chain_id":"bcb","height":11,"time":"2026-10-19T00:00:11Z","num_txs":0}},` +
+			`{"block_id":{"hash":"0A"},"header":{"chain_id":"bcb","height":10,"time":"2026-10-19T00:00:10Z","num_txs":0}}]}}`))
+	}))
+	defer node.Close()
+	c := common.NewChain(config.ChainProfile{Name: "blocks", ChainID: testChainID, ChainVersion: "2", NodeAddrSlice: []string{node.URL}})
+
+	result, err := blocks(c, 10, 11, 12, false)
+	assert.Nil(err)
+	if assert.Equal(2, len(result.Result)) {
+		assert.Equal(int64(10), result.Result[0].BlockHeight)
+		assert.Equal("0x0a", result.Result[0].BlockHash)
+		assert.Equal(int64(11), result.Result[1].BlockHeight)
+	}
+
+	result, err = blocks(c, 10, 11, 12, true)
+	assert.Nil(err)
+	if assert.Equal(2, len(result.Blocks)) {
+		assert.Equal(int64(10), result.Blocks[0].BlockHeight)
+		assert.Equal("bcb", result.Blocks[0].ChainID)
+		assert.Equal(0, len(result.Blocks[1].Txs))
+	}
+	assert.Equal(int32(0), atomic.LoadInt32(&blockCalls))
+}
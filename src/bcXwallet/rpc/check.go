@@ -6,6 +6,7 @@ import (
 	"errors"
 	"github.com/btcsuite/btcutil/base58"
 	"golang.org/x/crypto/ripemd160"
+	"math/big"
 	"regexp"
 	"strconv"
 	"strings"
@@ -102,3 +103,21 @@ func requireUint64(valueStr string) (uint64, error) {
 
 	return value, nil
 }
+
+// requireUint - parse a non-negative decimal integer without upper limit
+func requireUint(valueStr string) (*big.Int, error) {
+	valid, err := regexp.MatchString(`^[0-9]+$`, valueStr)
+	if err != nil {
+		return nil, errors.New("Regular expression error=" + err.Error())
+	}
+	if !valid {
+		return nil, errors.New("Value must be a non-negative integer, value=" + valueStr)
+	}
+
+	value, ok := new(big.Int).SetString(valueStr, 10)
+	if !ok {
+		return nil, errors.New("Value must be a non-negative integer, value=" + valueStr)
+	}
+
+	return value, nil
+}
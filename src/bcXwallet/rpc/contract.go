@@ -0,0 +1,141 @@
+package rpc
+
+import (
+	"bcXwallet/common"
+	"blockchain/smcsdk/sdk/std"
+	types3 "blockchain/types"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+)
+
+func checkContractChainVersion(c *common.Chain) error {
+	chainVersion, err := c.ChainVersion()
+	if err != nil {
+		return err
+	}
+
+	if chainVersion != "2" {
+		return errors.New("Contract and organization inspection needs chainVersion 2 ")
+	}
+
+	return nil
+}
+
+// effectiveAt - contract is effective from its effect height until its lose height
+func effectiveAt(contract *std.Contract, height int64) bool {
+	return contract.EffectHeight <= height && (contract.LoseHeight == 0 || contract.LoseHeight > height)
+}
+
+func methodResults(methods []std.Method) []MethodResult {
+	results := make([]MethodResult, 0, len(methods))
+	for _, method := range methods {
+		results = append(results, MethodResult{MethodID: method.MethodID, Gas: method.Gas, ProtoType: method.ProtoType})
+	}
+
+	return results
+}
+
+func contractInfo(c *common.Chain, contractAddress types3.Address, height int64) (result *ContractResult, err error) {
+
+	con, err := contractV2(c, contractAddress, height)
+	if err != nil {
+		return
+	}
+
+	result = &ContractResult{
+		Address:      con.Address,
+		Account:      con.Account,
+		Owner:        con.Owner,
+		Name:         con.Name,
+		Version:      con.Version,
+		CodeHash:     "0x" + hex.EncodeToString(con.CodeHash),
+		EffectHeight: con.EffectHeight,
+		LoseHeight:   con.LoseHeight,
+		Methods:      methodResults(con.Methods),
+		Interfaces:   methodResults(con.Interfaces),
+		Token:        con.Token,
+		OrgID:        con.OrgID,
+		ChainVersion: con.ChainVersion,
+		Height:       height,
+		Effective:    effectiveAt(con, height),
+	}
+
+	effective := con
+	if !result.Effective {
+		if effective, err = effectiveContractV2(c, con, height); err != nil {
+			return
+		}
+	}
+	if effectiveAt(effective, height) {
+		result.EffectiveAddress = effective.Address
+		result.EffectiveVersion = effective.Version
+	}
+
+	return
+}
+
+func contractVersions(c *common.Chain, orgID, name string, height int64) (result *ContractVersionsResult, err error) {
+
+	conVer, err := contractVersionsV2(c, orgID, name, height)
+	if err != nil {
+		return
+	}
+
+	result = &ContractVersionsResult{
+		OrgID:    orgID,
+		Name:     conVer.Name,
+		Height:   height,
+		Versions: make([]ContractVersionResult, 0, len(conVer.ContractAddrList)),
+	}
+	for _, address := range conVer.ContractAddrList {
+		var con *std.Contract
+		if con, err = contractV2(c, address, height); err != nil {
+			return
+		}
+
+		result.Versions = append(result.Versions,
+			ContractVersionResult{
+				Address:      con.Address,
+				Version:      con.Version,
+				EffectHeight: con.EffectHeight,
+				LoseHeight:   con.LoseHeight})
+
+		if effectiveAt(con, height) {
+			result.EffectiveAddress = con.Address
+			result.EffectiveVersion = con.Version
+		}
+	}
+
+	return
+}
+
+func organization(c *common.Chain, orgID string) (result *OrganizationResult, err error) {
+
+	value, err := common.DoHttpQuery(c, std.GetOrganizaitionInfo(orgID))
+	if err != nil {
+		return
+	}
+	if len(value) == 0 {
+		return nil, errors.New("invalid orgID")
+	}
+
+	org := new(std.Organization)
+	if err = json.Unmarshal(value, org); err != nil {
+		return
+	}
+
+	result = &OrganizationResult{
+		OrgID:            org.OrgID,
+		Name:             org.Name,
+		OrgOwner:         org.OrgOwner,
+		ContractAddrList: org.ContractAddrList,
+		OrgCodeHash:      "0x" + hex.EncodeToString(org.OrgCodeHash),
+		Signers:          make([]string, 0, len(org.Signers)),
+	}
+	for _, signer := range org.Signers {
+		result.Signers = append(result.Signers, "0x"+hex.EncodeToString(signer))
+	}
+
+	return
+}
@@ -0,0 +1,55 @@
+package rpc
+
+import (
+	"blockchain/smcsdk/sdk/std"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContractVersions(t *testing.T) {
+	assert := assert.New(t)
+
+	v1 := &std.Contract{Address: "local1", Name: "token-test", Version: "1.0", OrgID: "orgTest", EffectHeight: 10, LoseHeight: 100,
+		Methods: []std.Method{{MethodID: "44d8ca60", Gas: 500, ProtoType: "Transfer(types.Address,bn.Number)"}}}
+	v2 := &std.Contract{Address: "local2", Name: "token-test", Version: "2.0", OrgID: "orgTest", EffectHeight: 100}
+	metadata.set(metaKey(testChain, metaContractV2, std.KeyOfContract(v1.Address)), v1, heightOfImmutable)
+	metadata.set(metaKey(testChain, metaContractV2, std.KeyOfContract(v2.Address)), v2, heightOfImmutable)
+	metadata.set(metaKey(testChain, metaContractVerV2, std.KeyOfContractsWithName("orgTest", "token-test")),
+		&std.ContractVersionList{Name: "token-test", ContractAddrList: []string{"local1", "local2"}, EffectHeights: []int64{10, 100}},
+		heightOfImmutable)
+	defer func() {
+		metadata.remove(metaKey(testChain, metaContractV2, std.KeyOfContract(v1.Address)))
+		metadata.remove(metaKey(testChain, metaContractV2, std.KeyOfContract(v2.Address)))
+		metadata.remove(metaKey(testChain, metaContractVerV2, std.KeyOfContractsWithName("orgTest", "token-test")))
+	}()
+
+	versions, err := contractVersions(testChain, "orgTest", "token-test", 50)
+	assert.Nil(err)
+	assert.Equal(2, len(versions.Versions))
+	assert.Equal("local1", versions.EffectiveAddress)
+	assert.Equal("1.0", versions.EffectiveVersion)
+
+	versions, err = contractVersions(testChain, "orgTest", "token-test", 100)
+	assert.Nil(err)
+	assert.Equal("local2", versions.EffectiveAddress)
+	assert.Equal(int64(100), versions.Versions[0].LoseHeight)
+
+	// the old version is not effective after it's upgraded
+	result, err := contractInfo(testChain, "local1", 150)
+	assert.Nil(err)
+	assert.False(result.Effective)
+	assert.Equal("local2", result.EffectiveAddress)
+	assert.Equal("2.0", result.EffectiveVersion)
+	assert.Equal(int64(500), result.Methods[0].Gas)
+
+	result, err = contractInfo(testChain, "local1", 50)
+	assert.Nil(err)
+	assert.True(result.Effective)
+	assert.Equal("local1", result.EffectiveAddress)
+
+	// no version is effective before the first one
+	versions, err = contractVersions(testChain, "orgTest", "token-test", 5)
+	assert.Nil(err)
+	assert.Equal("", versions.EffectiveAddress)
+}
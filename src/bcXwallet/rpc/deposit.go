@@ -0,0 +1,242 @@
+package rpc
+
+import (
+	"bcXwallet/common"
+	"errors"
+	"strconv"
+	"sync"
+)
+
+const depositBlocksOnce = 100 // max count of blocks scanned by deposit watcher in one round
+
+var depositAckMtx sync.Mutex
+
+// StartDepositWatcher - start deposit watcher of every chain if depositEnabled is true in config
+func StartDepositWatcher() {
+	if !common.GetConfig().DepositEnabled {
+		return
+	}
+
+	for _, c := range common.Chains() {
+		go followBlocks(c, "deposit watcher", watchDeposits(c))
+	}
+}
+
+// watchDeposits - scan function of followBlocks, match deposits in blocks of chain after the scanned height
+func watchDeposits(c *common.Chain) func() (bool, error) {
+	scanned := func() (int64, error) { return depositScannedHeight(c) }
+
+	return func() (bool, error) {
+		addresses, err := watchedAddresses(c)
+		if err != nil {
+			return false, err
+		}
+
+		return scanBlocks(c, depositBlocksOnce, scanned, func(blk *BlockResult) error {
+			if err := saveDeposits(c, blk.BlockHeight, matchDeposits(blk, addresses)); err != nil {
+				return err
+			}
+
+			return notifyConfirmedDeposits(c, blk.BlockHeight)
+		})
+	}
+}
+
+// saveDeposits - save deposits of block of chain with their detected events, they are in one batch with scanned height
+// so an event is never lost when the watcher stops between them
+func saveDeposits(c *common.Chain, height int64, deposits []Deposit) error {
+	var urls []string
+	if webhookEnabled() {
+		urls = common.GetConfig().WebhookURLs
+	}
+
+	webhookMtx.Lock()
+	defer webhookMtx.Unlock()
+
+	if err := db.SaveDeposits(c.Name, height, deposits, urls); err != nil {
+		return err
+	}
+	if len(deposits) != 0 && len(urls) != 0 {
+		wakeupNotifier()
+	}
+
+	return nil
+}
+
+// watchedAddresses - addresses of all wallets in keystore and depositWatchList in config
+func watchedAddresses(c *common.Chain) (map[string]bool, error) {
+	addresses := make(map[string]bool)
+	for _, address := range common.GetConfig().DepositWatchList {
+		addresses[address] = true
+	}
+
+	wallets, err := walletAddresses(c)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, address := range wallets {
+		addresses[address] = true
+	}
+
+	return addresses, nil
+}
+
+// depositScannedHeight - the last height of chain scanned by deposit watcher, it's depositStartHeight-1
+// before the first block
+func depositScannedHeight(c *common.Chain) (int64, error) {
+	height, err := db.DepositScannedHeight(c.Name)
+	if err != nil || height > 0 {
+		return height, err
+	}
+
+	height = common.GetConfig().DepositStartHeight - 1
+	if height < 0 {
+		height = 0
+	}
+
+	return height, nil
+}
+
+func depositConfirmations() int64 {
+	if confirmations := common.GetConfig().DepositConfirmations; confirmations > 0 {
+		return confirmations
+	}
+
+	return 1
+}
+
+// matchDeposits - incoming transfers of watched addresses in block, include transfers in receipts of contract
+func matchDeposits(blk *BlockResult, addresses map[string]bool) []Deposit {
+	deposits := make([]Deposit, 0)
+
+	// fees are never incoming, so their token doesn't matter here
+	for _, entry := range indexBlock(blk, "") {
+		if entry.Item.Direction != directionIn || !addresses[entry.Address] {
+			continue
+		}
+
+		deposits = append(deposits, Deposit{
+			TxHash:  entry.Item.TxHash,
+			Height:  entry.Item.Height,
+			Address: entry.Address,
+			Token:   entry.Item.Token,
+			Value:   entry.Item.Value,
+			From:    entry.Item.From,
+		})
+	}
+
+	return deposits
+}
+
+// fillConfirmations - confirmations of deposit is the count of scanned blocks since it
+func fillConfirmations(deposit *Deposit, scannedHeight int64) {
+	deposit.Confirmations = scannedHeight - deposit.Height + 1
+	deposit.Confirmed = deposit.Confirmations >= depositConfirmations()
+}
+
+// notifyConfirmedDeposits - emit confirmed events of deposits of chain in order of id,
+// the id of the last notified deposit is saved so that each deposit is notified once
+func notifyConfirmedDeposits(c *common.Chain, scannedHeight int64) error {
+
+	notifiedID, err := db.DepositNotifiedID(c.Name)
+	if err != nil {
+		return err
+	}
+
+	lastID := notifiedID
+	for {
+		deposits, err := db.Deposits(c.Name, lastID, depositBlocksOnce, true)
+		if err != nil {
+			return err
+		}
+
+		confirmedAll := true
+		for _, deposit := range deposits {
+			fillConfirmations(&deposit, scannedHeight)
+			if !deposit.Confirmed {
+				confirmedAll = false
+				break
+			}
+
+			emitEvent(c, eventDepositConfirmed, deposit)
+			lastID = deposit.ID
+		}
+
+		if !confirmedAll || len(deposits) < depositBlocksOnce {
+			break
+		}
+	}
+
+	if lastID == notifiedID {
+		return nil
+	}
+
+	return db.SetDepositNotifiedID(c.Name, lastID)
+}
+
+func deposits(c *common.Chain, sinceID uint64, limit int) (result *DepositsResult, err error) {
+
+	if !common.GetConfig().DepositEnabled {
+		return nil, errors.New("Deposit watcher is not enabled ")
+	}
+
+	if limit <= 0 {
+		limit = defaultHistoryLimit
+	} else if limit > maxHistoryLimit {
+		limit = maxHistoryLimit
+	}
+
+	result = new(DepositsResult)
+	if result.ScannedHeight, err = depositScannedHeight(c); err != nil {
+		return
+	}
+
+	if result.Deposits, err = db.Deposits(c.Name, sinceID, limit, false); err != nil {
+		return
+	}
+
+	result.LastID = sinceID
+	for i := range result.Deposits {
+		fillConfirmations(&result.Deposits[i], result.ScannedHeight)
+		result.LastID = result.Deposits[i].ID
+	}
+
+	return
+}
+
+// depositAck - mark deposit of chain as consumed, it will not be returned by bcb_deposits again, ack again is ok
+func depositAck(c *common.Chain, id uint64) (result *Deposit, err error) {
+
+	if !common.GetConfig().DepositEnabled {
+		return nil, errors.New("Deposit watcher is not enabled ")
+	}
+
+	depositAckMtx.Lock()
+	defer depositAckMtx.Unlock()
+
+	if result, err = db.Deposit(c.Name, id); err != nil {
+		return
+	}
+	if result == nil {
+		return nil, errors.New("Deposit " + strconv.FormatUint(id, 10) + " does not exist ")
+	}
+
+	scannedHeight, err := depositScannedHeight(c)
+	if err != nil {
+		return
+	}
+	fillConfirmations(result, scannedHeight)
+
+	if result.Acked {
+		return
+	}
+	if !result.Confirmed {
+		return nil, errors.New("Deposit " + strconv.FormatUint(id, 10) + " is not confirmed yet ")
+	}
+
+	result.Acked = true
+	err = db.SetDeposit(c.Name, result)
+
+	return
+}
@@ -0,0 +1,149 @@
+package rpc
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchDeposits(t *testing.T) {
+	assert := assert.New(t)
+
+	blk := &BlockResult{
+		BlockHeight: 20,
+		Txs: []TxResult{
+			{
+				TxHash: "0x01",
+				Code:   200,
+				From:   "alice",
+				Receipts: []Receipt{
+					{Name: receiptTransfer, Token: "token", From: "alice", To: "bob", Value: "100"},
+					{Name: receiptTransfer, Token: "token", From: "contract", To: "carol", Value: "5"},
+					{Name: receiptFee, Token: "bcb", From: "bob", Value: "1250000"},
+				},
+			},
+			{
+				TxHash: "0x02",
+				Code:   500,
+				From:   "dave",
+				Receipts: []Receipt{
+					{Name: receiptTransfer, Token: "token", From: "dave", To: "bob", Value: "8"},
+					{Name: receiptFee, Token: "bcb", From: "dave", Value: "1250000"},
+				},
+			},
+		},
+	}
+
+	deposits := matchDeposits(blk, map[string]bool{"bob": true, "dave": true})
+	if assert.Equal(1, len(deposits)) {
+		assert.Equal(Deposit{TxHash: "0x01", Height: 20, Address: "bob", Token: "token", Value: "100", From: "alice"}, deposits[0])
+	}
+
+	deposit := deposits[0]
+	fillConfirmations(&deposit, 19)
+	assert.Equal(int64(0), deposit.Confirmations)
+	assert.False(deposit.Confirmed)
+
+	fillConfirmations(&deposit, 20)
+	assert.Equal(int64(1), deposit.Confirmations)
+	assert.True(deposit.Confirmed)
+}
+
+func TestDeposits(t *testing.T) {
+	assert := assert.New(t)
+	defer openTestDB(t)()
+
+	assert.Nil(db.SaveDeposits(testChain.Name, 1, []Deposit{{TxHash: "0x01", Height: 1}, {TxHash: "0x02", Height: 1}}, nil))
+	assert.Nil(db.SaveDeposits(testChain.Name, 2, nil, nil))
+	assert.Nil(db.SaveDeposits(testChain.Name, 3, []Deposit{{TxHash: "0x03", Height: 3}}, nil))
+
+	height, err := db.DepositScannedHeight(testChain.Name)
+	assert.Nil(err)
+	assert.Equal(int64(3), height)
+
+	deposits, err := db.Deposits(testChain.Name, 0, 10, false)
+	assert.Nil(err)
+	if assert.Equal(3, len(deposits)) {
+		assert.Equal(uint64(1), deposits[0].ID)
+		assert.Equal(uint64(3), deposits[2].ID)
+		assert.Equal("0x03", deposits[2].TxHash)
+	}
+
+	deposits, err = db.Deposits(testChain.Name, 1, 1, false)
+	assert.Nil(err)
+	if assert.Equal(1, len(deposits)) {
+		assert.Equal(uint64(2), deposits[0].ID)
+	}
+
+	// acked deposit is not listed again
+	deposit, err := db.Deposit(testChain.Name, 2)
+	assert.Nil(err)
+	deposit.Acked = true
+	assert.Nil(db.SetDeposit(testChain.Name, deposit))
+
+	deposits, err = db.Deposits(testChain.Name, 0, 10, false)
+	assert.Nil(err)
+	if assert.Equal(2, len(deposits)) {
+		assert.Equal(uint64(1), deposits[0].ID)
+		assert.Equal(uint64(3), deposits[1].ID)
+	}
+
+	deposit, err = db.Deposit(testChain.Name, 4)
+	assert.Nil(err)
+	assert.Nil(deposit)
+
+	// deposits of other chains are kept apart
+	deposits, err = db.Deposits("side", 0, 10, false)
+	assert.Nil(err)
+	assert.Equal(0, len(deposits))
+	height, err = db.DepositScannedHeight("side")
+	assert.Nil(err)
+	assert.Equal(int64(0), height)
+}
+
+func TestSaveDepositsWithEvents(t *testing.T) {
+	assert := assert.New(t)
+	defer openTestDB(t)()
+
+	// detected events are saved in the batch of deposits
+	assert.Nil(db.SaveDeposits(testChain.Name, 1, nil, []string{"http://127.0.0.1:1"}))
+	event, err := db.WebhookEvent(1)
+	assert.Nil(err)
+	assert.Nil(event)
+
+	assert.Nil(db.SaveDeposits(testChain.Name, 2, []Deposit{{TxHash: "0x01", Height: 2}}, []string{"http://127.0.0.1:1"}))
+	event, err = db.WebhookEvent(1)
+	if assert.Nil(err) && assert.NotNil(event) {
+		assert.Equal(eventDepositDetected, event.Type)
+		assert.Equal(testChain.Name, event.Chain)
+		assert.Contains(event.Data, `"txHash":"0x01"`)
+	}
+	deliveries, err := db.WebhookDeliveries(math.MaxInt64, 10)
+	assert.Nil(err)
+	assert.Equal(1, len(deliveries))
+}
+
+func TestNotifyConfirmedDeposits(t *testing.T) {
+	assert := assert.New(t)
+	defer openTestDB(t)()
+
+	assert.Nil(db.SaveDeposits(testChain.Name, 1, []Deposit{{TxHash: "0x01", Height: 1}}, nil))
+	assert.Nil(db.SaveDeposits(testChain.Name, 2, []Deposit{{TxHash: "0x02", Height: 2}}, nil))
+
+	assert.Nil(notifyConfirmedDeposits(testChain, 1))
+	id, err := db.DepositNotifiedID(testChain.Name)
+	assert.Nil(err)
+	assert.Equal(uint64(1), id)
+
+	// acked deposit is still notified
+	deposit, err := db.Deposit(testChain.Name, 2)
+	assert.Nil(err)
+	deposit.Acked = true
+	assert.Nil(db.SetDeposit(testChain.Name, deposit))
+
+	assert.Nil(notifyConfirmedDeposits(testChain, 2))
+	id, err = db.DepositNotifiedID(testChain.Name)
+	assert.Nil(err)
+	assert.Equal(uint64(2), id)
+}
@@ -4,11 +4,13 @@ import (
 	"bcXwallet/common"
 	"blockchain/abciapp_v1.0/keys"
 	"bufio"
+	rpctypes "common/rpc/lib/types"
 	"encoding/hex"
 	"errors"
 	"fmt"
 	"github.com/tendermint/go-crypto"
 	"io/ioutil"
+	"math/big"
 	"os"
 	"strings"
 )
@@ -18,11 +20,16 @@ var (
 )
 
 // WalletCreate - create wallet
-func WalletCreate(name, password string) (result *WalletCreateResult, err error) {
+func WalletCreate(name, password, chain string) (result *WalletCreateResult, err error) {
 	logger := common.GetLogger()
 
 	defer common.FuncRecover(logger, &err)
-	logger.Trace("bcb_walletCreate", "name", name)
+	logger.Trace("bcb_walletCreate", "name", name, "chain", chain)
+
+	c, err := common.GetChain(chain)
+	if err != nil {
+		return
+	}
 
 	if err = checkName(name); err != nil {
 		return
@@ -41,7 +48,7 @@ func WalletCreate(name, password string) (result *WalletCreateResult, err error)
 	}
 
 	result = new(WalletCreateResult)
-	result, err = walletCreate(name, password)
+	result, err = walletCreate(c, name, password)
 	if err != nil {
 		logger.Error("Cannot create wallet", "error", err)
 	}
@@ -50,11 +57,16 @@ func WalletCreate(name, password string) (result *WalletCreateResult, err error)
 }
 
 // WalletExport - export wallet
-func WalletExport(name, password, accessKey string, plainText bool) (result *WalletExportResult, err error) {
+func WalletExport(name, password, accessKey string, plainText bool, chain string) (result *WalletExportResult, err error) {
 	logger := common.GetLogger()
 
 	defer common.FuncRecover(logger, &err)
-	logger.Trace("bcb_walletExport", "name", name, "plainText", plainText)
+	logger.Trace("bcb_walletExport", "name", name, "plainText", plainText, "chain", chain)
+
+	c, err := common.GetChain(chain)
+	if err != nil {
+		return
+	}
 
 	if err = checkName(name); err != nil {
 		return
@@ -76,7 +88,7 @@ func WalletExport(name, password, accessKey string, plainText bool) (result *Wal
 		return nil, errors.New("The accessKey can not be empty ")
 	}
 
-	result, err = walletExport(name, password, accessKey, plainText)
+	result, err = walletExport(c, name, password, accessKey, plainText)
 	if err != nil {
 		logger.Error("Cannot export wallet", "error", err)
 	}
@@ -90,11 +102,16 @@ func WalletExport(name, password, accessKey string, plainText bool) (result *Wal
 }
 
 // WalletImport - import wallet
-func WalletImport(name, privateKey, password, accessKey string, plainText bool) (result *WalletImportResult, err error) {
+func WalletImport(name, privateKey, password, accessKey string, plainText bool, chain string) (result *WalletImportResult, err error) {
 	logger := common.GetLogger()
 
 	defer common.FuncRecover(logger, &err)
-	logger.Trace("bcb_walletImport", "name", name)
+	logger.Trace("bcb_walletImport", "name", name, "chain", chain)
+
+	c, err := common.GetChain(chain)
+	if err != nil {
+		return
+	}
 
 	if err = checkName(name); err != nil {
 		return
@@ -139,12 +156,12 @@ func WalletImport(name, privateKey, password, accessKey string, plainText bool)
 			}
 		}
 
-		result, err = walletImport(name, hex.EncodeToString(newPrivateKey2), password, accessKey, plainText)
+		result, err = walletImport(c, name, hex.EncodeToString(newPrivateKey2), password, accessKey, plainText)
 		if err != nil {
 			logger.Error("Cannot import wallet", "error", err)
 		}
 	} else {
-		result, err = walletImport(name, privateKey, password, accessKey, plainText)
+		result, err = walletImport(c, name, privateKey, password, accessKey, plainText)
 		if err != nil {
 			logger.Error("Cannot import wallet", "error", err)
 		}
@@ -154,13 +171,18 @@ func WalletImport(name, privateKey, password, accessKey string, plainText bool)
 }
 
 // WalletList - list wallet of local
-func WalletList(pageNum uint64) (result *WalletListResult, err error) {
+func WalletList(pageNum uint64, chain string) (result *WalletListResult, err error) {
 	logger := common.GetLogger()
 
 	defer common.FuncRecover(logger, &err)
-	logger.Trace("bcb_walletList")
+	logger.Trace("bcb_walletList", "chain", chain)
+
+	c, err := common.GetChain(chain)
+	if err != nil {
+		return
+	}
 
-	result, err = walletList(pageNum)
+	result, err = walletList(c, pageNum)
 	if err != nil {
 		logger.Error("Cannot list wallet", "error", err)
 	}
@@ -169,51 +191,82 @@ func WalletList(pageNum uint64) (result *WalletListResult, err error) {
 }
 
 // WalletTransfer - transfer token
-func WalletTransfer(name, accessKey string, walletParams TransferParam) (result *TransferResult, err error) {
+func WalletTransfer(name, accessKey string, walletParams TransferParam, requestID, chain string) (result *TransferResult, err error) {
 	logger := common.GetLogger()
 
 	defer common.FuncRecover(logger, &err)
-	logger.Trace("bcb_transfer", "name", name, "gasLimit", walletParams.GasLimit, "note", walletParams.Note, "to", walletParams.To, "Value", walletParams.Value)
+	logger.Trace("bcb_transfer", "name", name, "gasLimit", walletParams.GasLimit, "note", walletParams.Note, "to", walletParams.To, "Value", walletParams.Value, "amountUnit", walletParams.AmountUnit, "requestId", requestID, "chain", chain)
+
+	c, err := common.GetChain(chain)
+	if err != nil {
+		return
+	}
 
 	if err = checkName(name); err != nil {
 		return
 	}
 
+	if requestID != "" {
+		if err = checkRequestID(requestID); err != nil {
+			return
+		}
+	}
+
 	//parse gasLimit
 	gasLimit, err := requireUint64(walletParams.GasLimit)
 	if err != nil {
 		return
 	}
 
+	// convert value to cong
+	if err = checkAmountUnit(walletParams.AmountUnit); err != nil {
+		return
+	}
+	if walletParams.Value, err = valueToCong(walletParams.Value, walletParams.AmountUnit); err != nil {
+		return
+	}
+
 	// check value
-	if _, err = requireUint64(walletParams.Value); err != nil {
+	value, err := requireUint(walletParams.Value)
+	if err != nil {
 		return
 	}
 
 	// check smcAddress
-	if err = checkAddress(crypto.GetChainId(), walletParams.SmcAddress); err != nil {
+	if err = checkAddress(c.ChainID, walletParams.SmcAddress); err != nil {
 		return
 	}
 
 	// check to address
-	if err = checkAddress(crypto.GetChainId(), walletParams.To); err != nil {
+	if err = checkAddress(c.ChainID, walletParams.To); err != nil {
 		return
 	}
 
-	result, err = transfer(name, accessKey, gasLimit, walletParams)
+	result, err = transfer(c, name, accessKey, gasLimit, value, walletParams, requestID)
 	if err != nil {
 		logger.Error("Cannot transfer", "error", err)
+		return
+	}
+
+	if walletParams.AmountUnit == amountUnitToken {
+		result.Unit = amountUnitToken
+		result.FeeDecimal = congToToken(new(big.Int).SetUint64(result.Fee).String())
 	}
 
 	return
 }
 
 // WalletTransferOffline - pack transfer transaction offline
-func WalletTransferOffline(name, accessKey string, walletParams TransferOfflineParam) (result *TransferOfflineResult, err error) {
+func WalletTransferOffline(name, accessKey string, walletParams TransferOfflineParam, chain string) (result *TransferOfflineResult, err error) {
 	logger := common.GetLogger()
 
 	defer common.FuncRecover(logger, &err)
-	logger.Trace("bcb_transferOffline", "name", name, "gasLimit", walletParams.GasLimit, "note", walletParams.Note, "to", walletParams.To, "Value", walletParams.Value)
+	logger.Trace("bcb_transferOffline", "name", name, "gasLimit", walletParams.GasLimit, "note", walletParams.Note, "to", walletParams.To, "Value", walletParams.Value, "amountUnit", walletParams.AmountUnit, "chain", chain)
+
+	c, err := common.GetChain(chain)
+	if err != nil {
+		return
+	}
 
 	if err = checkName(name); err != nil {
 		return
@@ -225,22 +278,31 @@ func WalletTransferOffline(name, accessKey string, walletParams TransferOfflineP
 		return
 	}
 
+	// convert value to cong
+	if err = checkAmountUnit(walletParams.AmountUnit); err != nil {
+		return
+	}
+	if walletParams.Value, err = valueToCong(walletParams.Value, walletParams.AmountUnit); err != nil {
+		return
+	}
+
 	// check value
-	if _, err = requireUint64(walletParams.Value); err != nil {
+	value, err := requireUint(walletParams.Value)
+	if err != nil {
 		return
 	}
 
 	// check smcAddress
-	if err = checkAddress(crypto.GetChainId(), walletParams.SmcAddress); err != nil {
+	if err = checkAddress(c.ChainID, walletParams.SmcAddress); err != nil {
 		return
 	}
 
 	// check to address
-	if err = checkAddress(crypto.GetChainId(), walletParams.To); err != nil {
+	if err = checkAddress(c.ChainID, walletParams.To); err != nil {
 		return
 	}
 
-	result, err = walletTransferOffline(name, accessKey, gasLimit, walletParams)
+	result, err = walletTransferOffline(c, name, accessKey, gasLimit, value, walletParams)
 	if err != nil {
 		logger.Error("Cannot pack transfer transaction", "error", err)
 	}
@@ -248,178 +310,1160 @@ func WalletTransferOffline(name, accessKey string, walletParams TransferOfflineP
 	return
 }
 
-// BlockHeight - get current block height
-func BlockHeight() (result *BlockHeightResult, err error) {
+// MultisigCreate - create multi-signature transfer transaction, the first signer is the sender
+func MultisigCreate(walletParams MultisigParam, chain string) (result *MultisigTxResult, err error) {
+	logger := common.GetLogger()
+
+	defer common.FuncRecover(logger, &err)
+	logger.Trace("bcb_multisigCreate", "gasLimit", walletParams.GasLimit, "note", walletParams.Note, "to", walletParams.To, "Value", walletParams.Value, "amountUnit", walletParams.AmountUnit, "signers", walletParams.Signers, "threshold", walletParams.Threshold, "chain", chain)
+
+	c, err := common.GetChain(chain)
+	if err != nil {
+		return
+	}
+
+	//parse gasLimit
+	gasLimit, err := requireUint64(walletParams.GasLimit)
+	if err != nil {
+		return
+	}
+
+	// convert value to cong
+	if err = checkAmountUnit(walletParams.AmountUnit); err != nil {
+		return
+	}
+	if walletParams.Value, err = valueToCong(walletParams.Value, walletParams.AmountUnit); err != nil {
+		return
+	}
+
+	// check value
+	value, err := requireUint(walletParams.Value)
+	if err != nil {
+		return
+	}
+
+	// check smcAddress
+	if err = checkAddress(c.ChainID, walletParams.SmcAddress); err != nil {
+		return
+	}
+
+	// check to address
+	if err = checkAddress(c.ChainID, walletParams.To); err != nil {
+		return
+	}
+
+	// check signers and threshold
+	if err = checkSigners(c, walletParams.Signers, walletParams.Threshold); err != nil {
+		return
+	}
+
+	result, err = multisigCreate(c, gasLimit, value, walletParams)
+	if err != nil {
+		logger.Error("Cannot create multi-signature transaction", "error", err)
+	}
+
+	return
+}
+
+// MultisigSign - sign multi-signature transaction with wallet
+func MultisigSign(name, accessKey, id, chain string) (result *MultisigTxResult, err error) {
+	logger := common.GetLogger()
+
+	defer common.FuncRecover(logger, &err)
+	logger.Trace("bcb_multisigSign", "name", name, "id", id, "chain", chain)
+
+	c, err := common.GetChain(chain)
+	if err != nil {
+		return
+	}
+
+	if err = checkName(name); err != nil {
+		return
+	}
+
+	if id == "" {
+		return nil, errors.New("Id cannot be empty ")
+	}
+
+	result, err = multisigSign(c, name, accessKey, id)
+	if err != nil {
+		logger.Error("Cannot sign multi-signature transaction", "error", err)
+	}
+
+	return
+}
+
+// MultisigSigners - get signed and unsigned signers of multi-signature transaction
+func MultisigSigners(id, chain string) (result *MultisigSignersResult, err error) {
 	defer common.FuncRecover(common.GetLogger(), &err)
 
-	common.GetLogger().Trace("bcb_blockHeight")
+	common.GetLogger().Trace("bcb_multisigSigners", "id", id, "chain", chain)
 
-	result, err = blockHeight()
+	c, err := common.GetChain(chain)
 	if err != nil {
-		common.GetLogger().Error("Cannot get current block height", "error", err)
+		return
+	}
+
+	if id == "" {
+		return nil, errors.New("Id cannot be empty ")
+	}
+
+	result, err = multisigSigners(c, id)
+	if err != nil {
+		common.GetLogger().Error("Cannot get signers of multi-signature transaction", "error", err)
 	}
 
 	return
 }
 
-// Block - get block data with height
-func Block(height int64) (result *BlockResult, err error) {
+// MultisigExport - export multi-signature transaction with collected signatures
+func MultisigExport(id, chain string) (result *MultisigTxResult, err error) {
 	defer common.FuncRecover(common.GetLogger(), &err)
 
-	common.GetLogger().Trace("bcb_block", "height", height)
+	common.GetLogger().Trace("bcb_multisigExport", "id", id, "chain", chain)
 
-	// if height is 0, set it current height
-	if height == 0 {
-		var blkHeight *BlockHeightResult
-		if blkHeight, err = blockHeight(); err != nil {
-			common.GetLogger().Error("Cannot get current block height", "error", err)
-			return
-		}
-		height = blkHeight.LastBlock
-	} else if height < 0 {
-		return nil, errors.New("Height cannot be negative ")
+	c, err := common.GetChain(chain)
+	if err != nil {
+		return
 	}
 
-	result, err = block(height)
+	if id == "" {
+		return nil, errors.New("Id cannot be empty ")
+	}
+
+	result, err = multisigExport(c, id)
 	if err != nil {
-		common.GetLogger().Error("Cannot get block data", "height", height, "error", err)
+		common.GetLogger().Error("Cannot export multi-signature transaction", "error", err)
 	}
 
 	return
 }
 
-// Transaction - get transaction data with txHash
-func Transaction(txHash string) (result *TxResult, err error) {
+// MultisigImport - import multi-signature transaction and merge its signatures
+func MultisigImport(data, chain string) (result *MultisigTxResult, err error) {
 	defer common.FuncRecover(common.GetLogger(), &err)
 
-	common.GetLogger().Trace("bcb_transaction", "txHash", txHash)
+	common.GetLogger().Trace("bcb_multisigImport", "data", data, "chain", chain)
 
-	if txHash == "" {
-		return nil, errors.New("TxHash cannot be empty ")
+	c, err := common.GetChain(chain)
+	if err != nil {
+		return
 	}
-	if txHash[:2] == "0x" {
-		txHash = txHash[2:]
+
+	if data == "" {
+		return nil, errors.New("Data cannot be empty ")
 	}
 
-	result, err = transaction(txHash, nil)
+	result, err = multisigImport(c, data)
 	if err != nil {
-		common.GetLogger().Error("Cannot get transaction data", "error", err)
+		common.GetLogger().Error("Cannot import multi-signature transaction", "error", err)
 	}
 
 	return
 }
 
-// Balance - get balance of account address
-func Balance(address keys.Address) (result *BalanceResult, err error) {
+// MultisigFinalize - assemble multi-signature transaction when threshold is reached, commit it with bcb_commitTx
+func MultisigFinalize(id, chain string) (result *MultisigFinalizeResult, err error) {
 	defer common.FuncRecover(common.GetLogger(), &err)
 
-	common.GetLogger().Trace("bcb_balance", "address", address)
+	common.GetLogger().Trace("bcb_multisigFinalize", "id", id, "chain", chain)
 
-	if address == "" {
-		return nil, errors.New("Address cannot be empty ")
+	c, err := common.GetChain(chain)
+	if err != nil {
+		return
+	}
+
+	if id == "" {
+		return nil, errors.New("Id cannot be empty ")
+	}
+
+	result, err = multisigFinalize(c, id)
+	if err != nil {
+		common.GetLogger().Error("Cannot finalize multi-signature transaction", "error", err)
 	}
 
-	if err = checkAddress(crypto.GetChainId(), address); err != nil {
+	return
+}
+
+// BlockHeight - get current block height
+func BlockHeight(chain string) (result *BlockHeightResult, err error) {
+	defer common.FuncRecover(common.GetLogger(), &err)
+
+	common.GetLogger().Trace("bcb_blockHeight", "chain", chain)
+
+	c, err := common.GetChain(chain)
+	if err != nil {
 		return
 	}
 
-	result, err = balance(address)
+	result, err = blockHeight(c)
 	if err != nil {
-		common.GetLogger().Error("Cannot get balance", "error", err)
+		common.GetLogger().Error("Cannot get current block height", "error", err)
 	}
 
 	return
 }
 
-// BalanceOfToken - get balance of account address and token address
-func BalanceOfToken(address, tokenAddress keys.Address, tokenName string) (result *BalanceResult, err error) {
+// Status - get status of chain and node
+func Status(chain string) (result *StatusResult, err error) {
 	defer common.FuncRecover(common.GetLogger(), &err)
 
-	common.GetLogger().Trace("bcb_balanceOfToken", "address", address, "tokenAddress", tokenAddress, "tokenName", tokenName)
+	common.GetLogger().Trace("bcb_status", "chain", chain)
 
-	if address == "" {
-		return nil, errors.New("Address cannot be empty ")
+	c, err := common.GetChain(chain)
+	if err != nil {
+		return
+	}
+
+	result, err = status(c)
+	if err != nil {
+		common.GetLogger().Error("Cannot get status", "error", err)
 	}
 
-	if err = checkAddress(crypto.GetChainId(), address); err != nil {
+	return
+}
+
+// ChainVersion - get chain version that transactions are encoded with, it's queried from nodes now
+func ChainVersion(chain string) (result *ChainVersionResult, err error) {
+	defer common.FuncRecover(common.GetLogger(), &err)
+
+	common.GetLogger().Trace("bcb_chainVersion", "chain", chain)
+
+	c, err := common.GetChain(chain)
+	if err != nil {
 		return
 	}
 
-	if tokenAddress != "" {
-		if err = checkAddress(crypto.GetChainId(), tokenAddress); err != nil {
-			return
-		}
-	} else if tokenName == "" {
-		return nil, errors.New("TokenAddress and TokenName cannot empty with both ")
+	result, err = chainVersion(c)
+	if err != nil {
+		common.GetLogger().Error("Cannot get chain version", "error", err)
 	}
 
-	result, err = balanceOfToken(address, tokenAddress, tokenName)
+	return
+}
+
+// Validators - get validators of height
+func Validators(height int64, chain string) (result *ValidatorsResult, err error) {
+	defer common.FuncRecover(common.GetLogger(), &err)
+
+	common.GetLogger().Trace("bcb_validators", "height", height, "chain", chain)
+
+	c, err := common.GetChain(chain)
 	if err != nil {
-		common.GetLogger().Error("Cannot get balance of token", "error", err)
+		return
+	}
+
+	if height, err = latestHeight(c, height); err != nil {
+		return
+	}
+
+	result, err = validators(c, height)
+	if err != nil {
+		common.GetLogger().Error("Cannot get validators", "height", height, "error", err)
 	}
 
 	return
 }
 
-// AllBalance - get all token balance of account address
-func AllBalance(address keys.Address) (result *[]AllBalanceItemResult, err error) {
+// NetInfo - get network info of node
+func NetInfo(chain string) (result *NetInfoResult, err error) {
 	defer common.FuncRecover(common.GetLogger(), &err)
 
-	common.GetLogger().Trace("bcb_allBalance", "address", address)
+	common.GetLogger().Trace("bcb_netInfo", "chain", chain)
 
-	if err = checkAddress(crypto.GetChainId(), address); err != nil {
+	c, err := common.GetChain(chain)
+	if err != nil {
 		return
 	}
 
-	result, err = allBalance(address)
+	result, err = netInfo(c)
 	if err != nil {
-		common.GetLogger().Error("Cannot get all balance", "error", err)
+		common.GetLogger().Error("Cannot get net info", "error", err)
 	}
 
 	return
 }
 
-// Nonce - get nonce of account address
-func Nonce(address keys.Address) (result *NonceResult, err error) {
+// Block - get block data with height
+func Block(height int64, amountUnit, chain string) (result *BlockResult, err error) {
 	defer common.FuncRecover(common.GetLogger(), &err)
 
-	common.GetLogger().Trace("bcb_nonce", "address", address)
+	common.GetLogger().Trace("bcb_block", "height", height, "amountUnit", amountUnit, "chain", chain)
+
+	c, err := common.GetChain(chain)
+	if err != nil {
+		return
+	}
 
-	if err = checkAddress(crypto.GetChainId(), address); err != nil {
+	if err = checkAmountUnit(amountUnit); err != nil {
 		return
 	}
 
-	result, err = nonce(address)
+	// if height is 0, set it current height
+	if height == 0 {
+		var blkHeight *BlockHeightResult
+		if blkHeight, err = blockHeight(c); err != nil {
+			common.GetLogger().Error("Cannot get current block height", "error", err)
+			return
+		}
+		height = blkHeight.LastBlock
+	} else if height < 0 {
+		return nil, errors.New("Height cannot be negative ")
+	}
+
+	result, err = block(c, height)
 	if err != nil {
-		common.GetLogger().Error("Cannot get nonce", "error", err)
+		common.GetLogger().Error("Cannot get block data", "height", height, "error", err)
+		return
+	}
+
+	if amountUnit == amountUnitToken {
+		fillBlockResultDecimal(result)
 	}
 
 	return
 }
 
-// CommitTx - commit transaction
-func CommitTx(tx string) (result *CommitTxResult, err error) {
+// Blocks - get blocks in [minHeight, maxHeight], headers only or decoded transactions if detail is true
+func Blocks(minHeight, maxHeight int64, detail bool, amountUnit, chain string) (result *BlocksResult, err error) {
 	defer common.FuncRecover(common.GetLogger(), &err)
 
-	common.GetLogger().Trace("bcb_commitTx", "tx", tx)
+	common.GetLogger().Trace("bcb_blocks", "minHeight", minHeight, "maxHeight", maxHeight, "detail", detail, "amountUnit", amountUnit, "chain", chain)
 
-	if tx == "" {
-		return nil, errors.New("Tx cannot be empty ")
+	c, err := common.GetChain(chain)
+	if err != nil {
+		return
+	}
+
+	if err = checkAmountUnit(amountUnit); err != nil {
+		return
 	}
 
-	result, err = commitTx(tx)
+	if minHeight < 0 || maxHeight < 0 {
+		return nil, errors.New("Height cannot be negative ")
+	}
+
+	var blkHeight *BlockHeightResult
+	if blkHeight, err = blockHeight(c); err != nil {
+		common.GetLogger().Error("Cannot get current block height", "error", err)
+		return
+	}
+
+	// default range is from 1 to current height
+	if minHeight == 0 {
+		minHeight = 1
+	}
+	if maxHeight == 0 || maxHeight > blkHeight.LastBlock {
+		maxHeight = blkHeight.LastBlock
+	}
+	if minHeight > maxHeight {
+		return nil, fmt.Errorf("MinHeight %d cannot be greater than maxHeight %d ", minHeight, maxHeight)
+	}
+
+	result, err = blocks(c, minHeight, maxHeight, blkHeight.LastBlock, detail)
 	if err != nil {
-		common.GetLogger().Error("Cannot commit tx", "error", err)
+		common.GetLogger().Error("Cannot get blocks data", "minHeight", minHeight, "maxHeight", maxHeight, "error", err)
+		return
+	}
+
+	if amountUnit == amountUnitToken {
+		for i := range result.Blocks {
+			fillBlockResultDecimal(&result.Blocks[i])
+		}
 	}
 
 	return
 }
 
-// Version - return current app version
-func Version() (result *VersionResult, err error) {
+// Transaction - get transaction data with txHash
+func Transaction(txHash, amountUnit, chain string) (result *TxResult, err error) {
 	defer common.FuncRecover(common.GetLogger(), &err)
 
-	common.GetLogger().Trace("bcb_version")
+	common.GetLogger().Trace("bcb_transaction", "txHash", txHash, "amountUnit", amountUnit, "chain", chain)
+
+	c, err := common.GetChain(chain)
+	if err != nil {
+		return
+	}
+
+	if txHash == "" {
+		return nil, errors.New("TxHash cannot be empty ")
+	}
+	if err = checkAmountUnit(amountUnit); err != nil {
+		return
+	}
+	if txHash[:2] == "0x" {
+		txHash = txHash[2:]
+	}
+
+	result, err = transaction(c, txHash, nil)
+	if err != nil {
+		common.GetLogger().Error("Cannot get transaction data", "error", err)
+		return
+	}
+
+	if amountUnit == amountUnitToken {
+		fillTxResultDecimal(result)
+	}
+
+	return
+}
+
+// PendingTxs - get transactions in mempool sent from or to address, name of wallet, or all wallets if it's empty
+func PendingTxs(address, chain string) (result *PendingTxsResult, err error) {
+	defer common.FuncRecover(common.GetLogger(), &err)
+
+	common.GetLogger().Trace("bcb_pendingTxs", "address", address, "chain", chain)
+
+	c, err := common.GetChain(chain)
+	if err != nil {
+		return
+	}
+
+	result, err = pendingTxs(c, address)
+	if err != nil {
+		common.GetLogger().Error("Cannot get pending transactions", "error", err)
+	}
+
+	return
+}
+
+// Balance - get balance of account address
+func Balance(address keys.Address, amountUnit string, height int64, chain string) (result *BalanceResult, err error) {
+	defer common.FuncRecover(common.GetLogger(), &err)
+
+	common.GetLogger().Trace("bcb_balance", "address", address, "amountUnit", amountUnit, "height", height, "chain", chain)
+
+	c, err := common.GetChain(chain)
+	if err != nil {
+		return
+	}
+
+	if address == "" {
+		return nil, errors.New("Address cannot be empty ")
+	}
+
+	if err = checkAmountUnit(amountUnit); err != nil {
+		return
+	}
+
+	if err = checkAddress(c.ChainID, address); err != nil {
+		return
+	}
+
+	if err = checkHistoryHeight(c, height); err != nil {
+		return
+	}
+
+	result, err = balance(c, address, height)
+	if err != nil {
+		common.GetLogger().Error("Cannot get balance", "error", err)
+		return
+	}
+
+	if amountUnit == amountUnitToken {
+		result.Unit = amountUnitToken
+		result.BalanceDecimal = congToToken(result.Balance)
+	}
+
+	return
+}
+
+// BalanceOfToken - get balance of account address and token address
+func BalanceOfToken(address, tokenAddress keys.Address, tokenName, amountUnit string, height int64, chain string) (result *BalanceResult, err error) {
+	defer common.FuncRecover(common.GetLogger(), &err)
+
+	common.GetLogger().Trace("bcb_balanceOfToken", "address", address, "tokenAddress", tokenAddress, "tokenName", tokenName, "amountUnit", amountUnit, "height", height, "chain", chain)
+
+	c, err := common.GetChain(chain)
+	if err != nil {
+		return
+	}
+
+	if address == "" {
+		return nil, errors.New("Address cannot be empty ")
+	}
+
+	if err = checkAmountUnit(amountUnit); err != nil {
+		return
+	}
+
+	if err = checkAddress(c.ChainID, address); err != nil {
+		return
+	}
+
+	if tokenAddress != "" {
+		if err = checkAddress(c.ChainID, tokenAddress); err != nil {
+			return
+		}
+	} else if tokenName == "" {
+		return nil, errors.New("TokenAddress and TokenName cannot empty with both ")
+	}
+
+	if err = checkHistoryHeight(c, height); err != nil {
+		return
+	}
+
+	result, err = balanceOfToken(c, address, tokenAddress, tokenName, height)
+	if err != nil {
+		common.GetLogger().Error("Cannot get balance of token", "error", err)
+		return
+	}
+
+	if amountUnit == amountUnitToken {
+		result.Unit = amountUnitToken
+		result.BalanceDecimal = congToToken(result.Balance)
+	}
+
+	return
+}
+
+// AllBalance - get all token balance of account address
+func AllBalance(address keys.Address, amountUnit string, height int64, chain string) (result *[]AllBalanceItemResult, err error) {
+	defer common.FuncRecover(common.GetLogger(), &err)
+
+	common.GetLogger().Trace("bcb_allBalance", "address", address, "amountUnit", amountUnit, "height", height, "chain", chain)
+
+	c, err := common.GetChain(chain)
+	if err != nil {
+		return
+	}
+
+	if err = checkAddress(c.ChainID, address); err != nil {
+		return
+	}
+
+	if err = checkAmountUnit(amountUnit); err != nil {
+		return
+	}
+
+	if err = checkHistoryHeight(c, height); err != nil {
+		return
+	}
+
+	result, err = allBalance(c, address, height)
+	if err != nil {
+		common.GetLogger().Error("Cannot get all balance", "error", err)
+		return
+	}
+
+	if amountUnit == amountUnitToken {
+		for i := range *result {
+			(*result)[i].Unit = amountUnitToken
+			(*result)[i].BalanceDecimal = congToToken((*result)[i].Balance)
+		}
+	}
+
+	return
+}
+
+// Nonce - get nonce of account address
+func Nonce(address keys.Address, height int64, chain string) (result *NonceResult, err error) {
+	defer common.FuncRecover(common.GetLogger(), &err)
+
+	common.GetLogger().Trace("bcb_nonce", "address", address, "height", height, "chain", chain)
+
+	c, err := common.GetChain(chain)
+	if err != nil {
+		return
+	}
+
+	if err = checkAddress(c.ChainID, address); err != nil {
+		return
+	}
+
+	if err = checkHistoryHeight(c, height); err != nil {
+		return
+	}
+
+	result, err = nonce(c, address, height)
+	if err != nil {
+		common.GetLogger().Error("Cannot get nonce", "error", err)
+	}
+
+	return
+}
+
+// BalanceAtTime - get balance of account address and token address at the last block not later than timestamp
+func BalanceAtTime(address, token keys.Address, timestamp int64, amountUnit, chain string) (result *BalanceAtTimeResult, err error) {
+	defer common.FuncRecover(common.GetLogger(), &err)
+
+	common.GetLogger().Trace("bcb_balanceAtTime", "address", address, "token", token, "timestamp", timestamp, "amountUnit", amountUnit, "chain", chain)
+
+	c, err := common.GetChain(chain)
+	if err != nil {
+		return
+	}
+
+	if err = checkAddress(c.ChainID, address); err != nil {
+		return
+	}
+
+	if token != "" {
+		if err = checkAddress(c.ChainID, token); err != nil {
+			return
+		}
+	}
+
+	if timestamp <= 0 {
+		return nil, errors.New("Timestamp must be positive ")
+	}
+
+	if err = checkAmountUnit(amountUnit); err != nil {
+		return
+	}
+
+	result, err = balanceAtTime(c, address, token, timestamp)
+	if err != nil {
+		common.GetLogger().Error("Cannot get balance at time", "error", err)
+		return
+	}
+
+	if amountUnit == amountUnitToken {
+		result.Unit = amountUnitToken
+		result.BalanceDecimal = congToToken(result.Balance)
+	}
+
+	return
+}
+
+// Tokens - get all tokens of chain
+func Tokens(chain string) (result *TokensResult, err error) {
+	defer common.FuncRecover(common.GetLogger(), &err)
+
+	common.GetLogger().Trace("bcb_tokens", "chain", chain)
+
+	c, err := common.GetChain(chain)
+	if err != nil {
+		return
+	}
+
+	result, err = tokens(c)
+	if err != nil {
+		common.GetLogger().Error("Cannot get tokens", "error", err)
+	}
+
+	return
+}
+
+// TokenInfo - get token of address, name or symbol
+func TokenInfo(tokenAddress keys.Address, tokenName, tokenSymbol, chain string) (result *TokenInfoResult, err error) {
+	defer common.FuncRecover(common.GetLogger(), &err)
+
+	common.GetLogger().Trace("bcb_tokenInfo", "tokenAddress", tokenAddress, "tokenName", tokenName, "tokenSymbol", tokenSymbol, "chain", chain)
+
+	c, err := common.GetChain(chain)
+	if err != nil {
+		return
+	}
+
+	if tokenAddress != "" {
+		if err = checkAddress(c.ChainID, tokenAddress); err != nil {
+			return
+		}
+	} else if tokenName == "" && tokenSymbol == "" {
+		return nil, errors.New("TokenAddress, TokenName and TokenSymbol cannot empty with all ")
+	}
+
+	result, err = tokenInfo(c, tokenAddress, tokenName, tokenSymbol)
+	if err != nil {
+		common.GetLogger().Error("Cannot get token info", "error", err)
+	}
+
+	return
+}
+
+// checkHistoryHeight - height of history state cannot be negative or higher than current block height, 0 means latest
+func checkHistoryHeight(c *common.Chain, height int64) error {
+	if height < 0 {
+		return errors.New("Height cannot be negative ")
+	} else if height == 0 {
+		return nil
+	}
+
+	blkHeight, err := blockHeight(c)
+	if err != nil {
+		return err
+	}
+	if height > blkHeight.LastBlock {
+		return errors.New("Height cannot be higher than current block height ")
+	}
+
+	return nil
+}
+
+// latestHeight - height is current block height if it's 0, it cannot be higher than current block height
+func latestHeight(c *common.Chain, height int64) (int64, error) {
+	if height < 0 {
+		return 0, errors.New("Height cannot be negative ")
+	}
+
+	blkHeight, err := blockHeight(c)
+	if err != nil {
+		return 0, err
+	}
+	if height == 0 {
+		return blkHeight.LastBlock, nil
+	} else if height > blkHeight.LastBlock {
+		return 0, errors.New("Height cannot be higher than current block height ")
+	}
+
+	return height, nil
+}
+
+// Contract - get contract of address and its version that is effective at height
+func Contract(address keys.Address, height int64, chain string) (result *ContractResult, err error) {
+	defer common.FuncRecover(common.GetLogger(), &err)
+
+	common.GetLogger().Trace("bcb_contract", "address", address, "height", height, "chain", chain)
+
+	c, err := common.GetChain(chain)
+	if err != nil {
+		return
+	}
+
+	if err = checkContractChainVersion(c); err != nil {
+		return
+	}
+
+	if err = checkAddress(c.ChainID, address); err != nil {
+		return
+	}
+
+	if height, err = latestHeight(c, height); err != nil {
+		return
+	}
+
+	result, err = contractInfo(c, address, height)
+	if err != nil {
+		common.GetLogger().Error("Cannot get contract", "error", err)
+	}
+
+	return
+}
+
+// ContractVersions - get versions of contract with organization ID and name
+func ContractVersions(orgID, name string, height int64, chain string) (result *ContractVersionsResult, err error) {
+	defer common.FuncRecover(common.GetLogger(), &err)
+
+	common.GetLogger().Trace("bcb_contractVersions", "orgID", orgID, "name", name, "height", height, "chain", chain)
+
+	c, err := common.GetChain(chain)
+	if err != nil {
+		return
+	}
+
+	if err = checkContractChainVersion(c); err != nil {
+		return
+	}
+
+	if orgID == "" {
+		return nil, errors.New("OrgID cannot be empty ")
+	}
+
+	if name == "" {
+		return nil, errors.New("Name cannot be empty ")
+	}
+
+	if height, err = latestHeight(c, height); err != nil {
+		return
+	}
+
+	result, err = contractVersions(c, orgID, name, height)
+	if err != nil {
+		common.GetLogger().Error("Cannot get contract versions", "error", err)
+	}
+
+	return
+}
+
+// Organization - get organization of ID
+func Organization(orgID, chain string) (result *OrganizationResult, err error) {
+	defer common.FuncRecover(common.GetLogger(), &err)
+
+	common.GetLogger().Trace("bcb_organization", "orgID", orgID, "chain", chain)
+
+	c, err := common.GetChain(chain)
+	if err != nil {
+		return
+	}
+
+	if err = checkContractChainVersion(c); err != nil {
+		return
+	}
+
+	if orgID == "" {
+		return nil, errors.New("OrgID cannot be empty ")
+	}
+
+	result, err = organization(c, orgID)
+	if err != nil {
+		common.GetLogger().Error("Cannot get organization", "error", err)
+	}
+
+	return
+}
+
+// CommitTx - commit transaction
+func CommitTx(tx, requestID, chain string) (result *CommitTxResult, err error) {
+	defer common.FuncRecover(common.GetLogger(), &err)
+
+	common.GetLogger().Trace("bcb_commitTx", "tx", tx, "requestId", requestID, "chain", chain)
+
+	c, err := common.GetChain(chain)
+	if err != nil {
+		return
+	}
+
+	if tx == "" {
+		return nil, errors.New("Tx cannot be empty ")
+	}
+
+	if requestID != "" {
+		if err = checkRequestID(requestID); err != nil {
+			return
+		}
+	}
+
+	if requestID == "" {
+		result, err = commitTx(c, tx)
+	} else {
+		result, _, err = commitWithRequestID(c, requestID, "bcb_commitTx", hashOfParams("bcb_commitTx", tx), func() (string, error) { return tx, nil })
+	}
+	if err != nil {
+		common.GetLogger().Error("Cannot commit tx", "error", err)
+	}
+
+	return
+}
+
+// SignedQuery - query state of key with the <qy> envelope signed by wallet
+func SignedQuery(name, accessKey, key, chain string) (result *SignedQueryResult, err error) {
+	logger := common.GetLogger()
+
+	defer common.FuncRecover(logger, &err)
+	logger.Trace("bcb_signedQuery", "name", name, "key", key, "chain", chain)
+
+	c, err := common.GetChain(chain)
+	if err != nil {
+		return
+	}
+
+	if err = checkName(name); err != nil {
+		return
+	}
+
+	if key == "" {
+		return nil, errors.New("Key cannot be empty ")
+	}
+
+	result, err = signedQuery(c, name, accessKey, key)
+	if err != nil {
+		logger.Error("Cannot query with signature", "error", err)
+	}
+
+	return
+}
+
+// AddressHistory - get transfers and fees of address from local indexer
+func AddressHistory(address, token string, fromHeight, toHeight int64, cursor string, limit int, chain string) (result *AddressHistoryResult, err error) {
+	defer common.FuncRecover(common.GetLogger(), &err)
+
+	common.GetLogger().Trace("bcb_addressHistory", "address", address, "token", token, "fromHeight", fromHeight, "toHeight", toHeight, "cursor", cursor, "limit", limit, "chain", chain)
+
+	c, err := common.GetChain(chain)
+	if err != nil {
+		return
+	}
+
+	if err = checkAddress(c.ChainID, address); err != nil {
+		return
+	}
+
+	if token != "" {
+		if err = checkAddress(c.ChainID, token); err != nil {
+			return
+		}
+	}
+
+	if fromHeight < 0 || toHeight < 0 {
+		return nil, errors.New("Height cannot be negative ")
+	}
+
+	if toHeight > 0 && fromHeight > toHeight {
+		return nil, fmt.Errorf("FromHeight %d cannot be greater than toHeight %d ", fromHeight, toHeight)
+	}
+
+	result, err = addressHistory(c, address, token, fromHeight, toHeight, cursor, limit)
+	if err != nil {
+		common.GetLogger().Error("Cannot get address history", "address", address, "error", err)
+	}
+
+	return
+}
+
+// Statement - get statement of address in token between fromTime and toTime in csv or jsonl format
+func Statement(address, token keys.Address, fromTime, toTime int64, format, chain string) (result *StatementResult, err error) {
+	defer common.FuncRecover(common.GetLogger(), &err)
+
+	common.GetLogger().Trace("bcb_statement", "address", address, "token", token, "fromTime", fromTime, "toTime", toTime, "format", format, "chain", chain)
+
+	c, err := common.GetChain(chain)
+	if err != nil {
+		return
+	}
+
+	if err = checkAddress(c.ChainID, address); err != nil {
+		return
+	}
+
+	if token != "" {
+		if err = checkAddress(c.ChainID, token); err != nil {
+			return
+		}
+	}
+
+	if fromTime <= 0 || toTime <= 0 {
+		return nil, errors.New("Timestamp must be positive ")
+	}
+
+	if fromTime > toTime {
+		return nil, fmt.Errorf("FromTime %d cannot be later than toTime %d ", fromTime, toTime)
+	}
+
+	if err = checkStatementFormat(format); err != nil {
+		return
+	}
+
+	result, err = statement(c, address, token, fromTime, toTime, format)
+	if err != nil {
+		common.GetLogger().Error("Cannot get statement", "address", address, "error", err)
+	}
+
+	return
+}
+
+// ReconcileReport - get balance reconciliation report of snapshot at height, 0 means the latest one
+func ReconcileReport(height int64, chain string) (result *ReconcileReportResult, err error) {
+	defer common.FuncRecover(common.GetLogger(), &err)
+
+	common.GetLogger().Trace("bcb_reconcileReport", "height", height, "chain", chain)
+
+	c, err := common.GetChain(chain)
+	if err != nil {
+		return
+	}
+
+	if height < 0 {
+		return nil, errors.New("Height cannot be negative ")
+	}
+
+	result, err = reconcileReport(c, height)
+	if err != nil {
+		common.GetLogger().Error("Cannot get reconcile report", "height", height, "error", err)
+	}
+
+	return
+}
+
+// Deposits - get deposits that are not acked with id greater than sinceId
+func Deposits(sinceID uint64, limit int, chain string) (result *DepositsResult, err error) {
+	defer common.FuncRecover(common.GetLogger(), &err)
+
+	common.GetLogger().Trace("bcb_deposits", "sinceId", sinceID, "limit", limit, "chain", chain)
+
+	c, err := common.GetChain(chain)
+	if err != nil {
+		return
+	}
+
+	result, err = deposits(c, sinceID, limit)
+	if err != nil {
+		common.GetLogger().Error("Cannot get deposits", "error", err)
+	}
+
+	return
+}
+
+// DepositAck - ack the confirmed deposit after it is consumed
+func DepositAck(id uint64, chain string) (result *Deposit, err error) {
+	defer common.FuncRecover(common.GetLogger(), &err)
+
+	common.GetLogger().Trace("bcb_depositAck", "id", id, "chain", chain)
+
+	c, err := common.GetChain(chain)
+	if err != nil {
+		return
+	}
+
+	if id == 0 {
+		return nil, errors.New("Id cannot be empty ")
+	}
+
+	result, err = depositAck(c, id)
+	if err != nil {
+		common.GetLogger().Error("Cannot ack deposit", "id", id, "error", err)
+	}
+
+	return
+}
+
+// WebhookReplay - deliver webhook event to all webhook urls again
+func WebhookReplay(id uint64, chain string) (result *WebhookEvent, err error) {
+	defer common.FuncRecover(common.GetLogger(), &err)
+
+	common.GetLogger().Trace("bcb_webhookReplay", "id", id, "chain", chain)
+
+	c, err := common.GetChain(chain)
+	if err != nil {
+		return
+	}
+
+	if id == 0 {
+		return nil, errors.New("Id cannot be empty ")
+	}
+
+	result, err = webhookReplay(c, id)
+	if err != nil {
+		common.GetLogger().Error("Cannot replay webhook event", "id", id, "error", err)
+	}
+
+	return
+}
+
+// Nodes - get health, latency and circuit state of nodes
+func Nodes(chain string) (result *NodesResult, err error) {
+	defer common.FuncRecover(common.GetLogger(), &err)
+
+	common.GetLogger().Trace("bcb_nodes", "chain", chain)
+
+	c, err := common.GetChain(chain)
+	if err != nil {
+		return
+	}
+
+	pool := common.GetNodePool(c.NodeAddrSlice)
+
+	result = new(NodesResult)
+	result.Nodes = pool.Nodes()
+	result.QuorumSize = common.GetConfig().QuorumSize
+	result.QuorumReads, result.QuorumDivergences = pool.QuorumStats()
+
+	return
+}
+
+// MetaCacheStats - get size and hit/miss counters of metadata cache
+func MetaCacheStats(chain string) (result *MetaCacheStatsResult, err error) {
+	defer common.FuncRecover(common.GetLogger(), &err)
+
+	common.GetLogger().Trace("bcb_metaCacheStats", "chain", chain)
+
+	if _, err = common.GetChain(chain); err != nil {
+		return
+	}
+
+	return metadata.stats(), nil
+}
+
+// Subscribe - subscribe websocket topic newBlock, walletTx:<name|address> or txStatus:<hash>
+func Subscribe(wsCtx rpctypes.WSRPCContext, topic, chain string) (result *SubscribeResult, err error) {
+	defer common.FuncRecover(common.GetLogger(), &err)
+
+	common.GetLogger().Trace("subscribe", "remote", wsCtx.GetRemoteAddr(), "topic", topic, "chain", chain)
+
+	c, err := common.GetChain(chain)
+	if err != nil {
+		return
+	}
+
+	if topic == "" {
+		return nil, errors.New("Topic cannot be empty ")
+	}
+
+	result, err = subscribe(c, wsCtx, topic)
+	if err != nil {
+		common.GetLogger().Error("Cannot subscribe", "topic", topic, "error", err)
+	}
+
+	return
+}
+
+// Unsubscribe - unsubscribe websocket topic
+func Unsubscribe(wsCtx rpctypes.WSRPCContext, topic, chain string) (result *SubscribeResult, err error) {
+	defer common.FuncRecover(common.GetLogger(), &err)
+
+	common.GetLogger().Trace("unsubscribe", "remote", wsCtx.GetRemoteAddr(), "topic", topic, "chain", chain)
+
+	c, err := common.GetChain(chain)
+	if err != nil {
+		return
+	}
+
+	if topic == "" {
+		return nil, errors.New("Topic cannot be empty ")
+	}
+
+	result, err = unsubscribe(c, wsCtx, topic)
+	if err != nil {
+		common.GetLogger().Error("Cannot unsubscribe", "topic", topic, "error", err)
+	}
+
+	return
+}
+
+// UnsubscribeAll - unsubscribe all websocket topics of the connection on every chain
+func UnsubscribeAll(wsCtx rpctypes.WSRPCContext, chain string) (result *SubscribeResult, err error) {
+	defer common.FuncRecover(common.GetLogger(), &err)
+
+	common.GetLogger().Trace("unsubscribe_all", "remote", wsCtx.GetRemoteAddr(), "chain", chain)
+
+	if _, err = common.GetChain(chain); err != nil {
+		return
+	}
+
+	result, err = unsubscribeAll(wsCtx)
+	if err != nil {
+		common.GetLogger().Error("Cannot unsubscribe all", "error", err)
+	}
+
+	return
+}
+
+// ConvertUnit - convert value between cong and token
+func ConvertUnit(value, amountUnit, chain string) (result *ConvertUnitResult, err error) {
+	defer common.FuncRecover(common.GetLogger(), &err)
+
+	common.GetLogger().Trace("bcb_convertUnit", "value", value, "amountUnit", amountUnit, "chain", chain)
+
+	if _, err = common.GetChain(chain); err != nil {
+		return
+	}
+
+	if value == "" {
+		return nil, errors.New("Value cannot be empty ")
+	}
+
+	if err = checkAmountUnit(amountUnit); err != nil {
+		return
+	}
+
+	result, err = convertUnit(value, amountUnit)
+	if err != nil {
+		common.GetLogger().Error("Cannot convert unit", "error", err)
+	}
+
+	return
+}
+
+// Version - return current app version
+func Version(chain string) (result *VersionResult, err error) {
+	defer common.FuncRecover(common.GetLogger(), &err)
+
+	common.GetLogger().Trace("bcb_version", "chain", chain)
+
+	if _, err = common.GetChain(chain); err != nil {
+		return
+	}
 
 	var version []byte
 	version, err = ioutil.ReadFile("./.config/version")
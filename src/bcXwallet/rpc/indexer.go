@@ -0,0 +1,214 @@
+package rpc
+
+import (
+	"bcXwallet/common"
+	"blockchain/abciapp_v1.0/keys"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	types2 "github.com/tendermint/abci/types"
+)
+
+// direction of address history item
+const (
+	directionIn  = "in"
+	directionOut = "out"
+	directionFee = "fee"
+)
+
+const (
+	indexerInterval        = 3 * time.Second // wait time after indexer caught up or failed
+	indexerBlocksOnce      = 100             // max count of blocks indexed in one round
+	defaultHistoryLimit    = 100
+	maxHistoryLimit        = 1000
+	historyCursorPattern   = `^\d{20}/\d{6}/\d{4}$`
+	historyHeightKeyFormat = "%020d"
+)
+
+// historyEntry - history item of address to save, suffix orders items of address by height
+type historyEntry struct {
+	Address keys.Address
+	Suffix  string
+	Item    HistoryItem
+}
+
+// StartIndexer - start address history indexer of every chain if indexerEnabled is true in config
+func StartIndexer() {
+	if !common.GetConfig().IndexerEnabled {
+		return
+	}
+
+	for _, c := range common.Chains() {
+		go followBlocks(c, "address history indexer", indexBlocks(c))
+	}
+}
+
+// indexBlocks - scan function of followBlocks, index blocks of chain after the indexed height
+func indexBlocks(c *common.Chain) func() (bool, error) {
+	scanned := func() (int64, error) { return indexedHeight(c) }
+
+	return func() (bool, error) {
+		return scanBlocks(c, indexerBlocksOnce, scanned, func(blk *BlockResult) error {
+			// fee of transaction without receipt is paid with genesis token
+			token, err := genesisTokenOf(c)
+			if err != nil {
+				return err
+			}
+
+			return db.SaveHistory(c.Name, blk.BlockHeight, indexBlock(blk, token.Address))
+		})
+	}
+}
+
+// followBlocks - call scan of chain repeatedly, wait for a while after it caught up or failed
+func followBlocks(c *common.Chain, name string, scan func() (caughtUp bool, err error)) {
+	logger := common.GetLogger()
+
+	for {
+		caughtUp, err := scan()
+		if err != nil {
+			logger.Error("Cannot scan blocks", "scanner", name, "chain", c.Name, "error", err)
+		}
+
+		if err != nil || caughtUp {
+			time.Sleep(indexerInterval)
+		}
+	}
+}
+
+// scanBlocks - save at most count blocks after the scanned height, save must set the scanned height with block atomically
+func scanBlocks(c *common.Chain, count int64, scanned func() (int64, error), save func(blk *BlockResult) error) (caughtUp bool, err error) {
+
+	height, err := scanned()
+	if err != nil {
+		return
+	}
+
+	blkHeight, err := blockHeight(c)
+	if err != nil {
+		return
+	}
+
+	for h := height + 1; h <= blkHeight.LastBlock && h <= height+count; h++ {
+		var blk *BlockResult
+		if blk, err = block(c, h); err != nil {
+			return
+		}
+
+		if err = save(blk); err != nil {
+			return
+		}
+	}
+
+	return height+count >= blkHeight.LastBlock, nil
+}
+
+// indexedHeight - the last indexed height of chain, it's indexerStartHeight-1 before the first block is indexed
+func indexedHeight(c *common.Chain) (int64, error) {
+	height, err := db.IndexedHeight(c.Name)
+	if err != nil || height > 0 {
+		return height, err
+	}
+
+	height = common.GetConfig().IndexerStartHeight - 1
+	if height < 0 {
+		height = 0
+	}
+
+	return height, nil
+}
+
+// indexBlock - history entries of all addresses in block, transfers and fees are taken from receipts,
+// or from transfer messages and fee of transaction paid with feeToken if transaction has no receipt (chain version 1)
+func indexBlock(blk *BlockResult, feeToken keys.Address) []historyEntry {
+	entries := make([]historyEntry, 0)
+
+	for txIndex, tx := range blk.Txs {
+		seq := 0
+		add := func(address keys.Address, item HistoryItem) {
+			item.TxHash = tx.TxHash
+			item.Height = blk.BlockHeight
+			entries = append(entries, historyEntry{
+				Address: address,
+				Suffix:  fmt.Sprintf(historyHeightKeyFormat+"/%06d/%04d", blk.BlockHeight, txIndex, seq),
+				Item:    item,
+			})
+			seq++
+		}
+
+		hasReceipt := false
+		for _, receipt := range tx.Receipts {
+			switch receipt.Name {
+			case receiptTransfer:
+				hasReceipt = true
+				if tx.Code != types2.CodeTypeOK {
+					continue
+				}
+				item := HistoryItem{Token: receipt.Token, Value: receipt.Value, From: receipt.From, To: receipt.To}
+				item.Direction = directionOut
+				add(receipt.From, item)
+				item.Direction = directionIn
+				add(receipt.To, item)
+			case receiptFee:
+				hasReceipt = true
+				add(receipt.From, HistoryItem{Direction: directionFee, Token: receipt.Token, Value: receipt.Value, From: receipt.From})
+			}
+		}
+
+		if hasReceipt {
+			continue
+		}
+
+		if tx.Fee > 0 {
+			add(tx.From, HistoryItem{Direction: directionFee, Token: feeToken, Value: strconv.FormatUint(tx.Fee, 10), From: tx.From})
+		}
+		if tx.Code != types2.CodeTypeOK {
+			continue
+		}
+
+		for _, msg := range tx.Messages {
+			if msg.To == "" {
+				continue
+			}
+			item := HistoryItem{Token: msg.SmcAddress, Value: msg.Value, From: tx.From, To: msg.To}
+			item.Direction = directionOut
+			add(tx.From, item)
+			item.Direction = directionIn
+			add(msg.To, item)
+		}
+	}
+
+	return entries
+}
+
+func addressHistory(c *common.Chain, address, token keys.Address, fromHeight, toHeight int64, cursor string, limit int) (result *AddressHistoryResult, err error) {
+
+	if !common.GetConfig().IndexerEnabled {
+		return nil, errors.New("Address history indexer is not enabled ")
+	}
+
+	if cursor != "" {
+		if ok, _ := regexp.MatchString(historyCursorPattern, cursor); !ok {
+			return nil, errors.New("Invalid cursor ")
+		}
+	}
+
+	if limit <= 0 {
+		limit = defaultHistoryLimit
+	} else if limit > maxHistoryLimit {
+		limit = maxHistoryLimit
+	}
+
+	result = new(AddressHistoryResult)
+	result.Address = address
+	if result.IndexedHeight, err = indexedHeight(c); err != nil {
+		return
+	}
+
+	result.Items, result.NextCursor, err = db.History(c.Name, address, token, fromHeight, toHeight, cursor, limit)
+
+	return
+}
@@ -0,0 +1,123 @@
+package rpc
+
+import (
+	"bcXwallet/common"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIndexBlock(t *testing.T) {
+	assert := assert.New(t)
+
+	blk := &BlockResult{
+		BlockHeight: 12,
+		Txs: []TxResult{
+			{
+				TxHash: "0x01",
+				Code:   200,
+				From:   "alice",
+				Receipts: []Receipt{
+					{Name: receiptTransfer, Token: "token", From: "alice", To: "bob", Value: "100"},
+					{Name: receiptTransfer, Token: "token", From: "contract", To: "carol", Value: "5"},
+					{Name: receiptFee, Token: "bcb", From: "alice", Value: "1250000"},
+				},
+			},
+			{
+				TxHash:   "0x02",
+				Code:     200,
+				From:     "dave",
+				Fee:      1500000,
+				Messages: []Message{{SmcAddress: "token", To: "bob", Value: "7"}},
+			},
+			{
+				TxHash:   "0x03",
+				Code:     500,
+				From:     "dave",
+				Fee:      1000,
+				Messages: []Message{{SmcAddress: "token", To: "bob", Value: "8"}},
+			},
+		},
+	}
+
+	entries := indexBlock(blk, "bcb")
+	if !assert.Equal(9, len(entries)) {
+		return
+	}
+
+	assert.Equal("alice", entries[0].Address)
+	assert.Equal(directionOut, entries[0].Item.Direction)
+	assert.Equal("bob", entries[1].Address)
+	assert.Equal(directionIn, entries[1].Item.Direction)
+	assert.Equal("carol", entries[3].Address)
+	assert.Equal(directionFee, entries[4].Item.Direction)
+	assert.Equal("00000000000000000012/000000/0004", entries[4].Suffix)
+
+	// transfer and fee of chain version 1 without receipt, failed transaction pays fee too
+	assert.Equal(HistoryItem{TxHash: "0x02", Height: 12, Direction: directionFee, Token: "bcb", Value: "1500000", From: "dave"}, entries[5].Item)
+	assert.Equal(HistoryItem{TxHash: "0x02", Height: 12, Direction: directionIn, Token: "token", Value: "7", From: "dave", To: "bob"}, entries[7].Item)
+	assert.Equal(HistoryItem{TxHash: "0x03", Height: 12, Direction: directionFee, Token: "bcb", Value: "1000", From: "dave"}, entries[8].Item)
+}
+
+func TestHistory(t *testing.T) {
+	assert := assert.New(t)
+	defer openTestDB(t)()
+
+	for height := int64(1); height <= 5; height++ {
+		blk := &BlockResult{
+			BlockHeight: height,
+			Txs: []TxResult{{
+				TxHash: "0x01",
+				Code:   200,
+				Receipts: []Receipt{
+					{Name: receiptTransfer, Token: "token", From: "alice", To: "bob", Value: "1"},
+					{Name: receiptTransfer, Token: "other", From: "alice", To: "bob", Value: "2"},
+				},
+			}},
+		}
+		assert.Nil(db.SaveHistory(testChain.Name, height, indexBlock(blk, "bcb")))
+	}
+
+	// an address with the same prefix must not be listed
+	assert.Nil(db.SaveHistory(testChain.Name, 6, indexBlock(&BlockResult{BlockHeight: 6, Txs: []TxResult{{
+		Code:     200,
+		Receipts: []Receipt{{Name: receiptTransfer, Token: "token", From: "alice2", To: "bob2", Value: "3"}},
+	}}}, "bcb")))
+
+	height, err := db.IndexedHeight(testChain.Name)
+	assert.Nil(err)
+	assert.Equal(int64(6), height)
+
+	items, cursor, err := db.History(testChain.Name, "alice", "", 0, 0, "", 4)
+	assert.Nil(err)
+	assert.Equal(4, len(items))
+	assert.Equal("00000000000000000002/000000/0002", cursor)
+
+	items, cursor, err = db.History(testChain.Name, "alice", "", 0, 0, cursor, 10)
+	assert.Nil(err)
+	assert.Equal(6, len(items))
+	assert.Equal("", cursor)
+	assert.Equal(int64(5), items[5].Height)
+
+	items, _, err = db.History(testChain.Name, "alice", "token", 2, 3, "", 10)
+	assert.Nil(err)
+	if assert.Equal(2, len(items)) {
+		assert.Equal(int64(2), items[0].Height)
+		assert.Equal(int64(3), items[1].Height)
+		assert.Equal("token", items[1].Token)
+	}
+
+	// history of other chains is kept apart
+	items, _, err = db.History("side", "alice", "", 0, 0, "", 10)
+	assert.Nil(err)
+	assert.Equal(0, len(items))
+}
+
+func TestKeyOfFollower(t *testing.T) {
+	assert := assert.New(t)
+
+	// the top-level chain keeps keys from before multiple chains are supported
+	assert.Equal("/bcbXWallet/history/alice/1", string(keyOfHistory(common.GetConfig().ChainID, "alice", "1")))
+	assert.Equal("/bcbXWallet/chain/side/history/alice/1", string(keyOfHistory("side", "alice", "1")))
+	assert.Equal("/bcbXWallet/chain/side/deposit/item/00000000000000000001", string(keyOfDeposit("side", 1)))
+}
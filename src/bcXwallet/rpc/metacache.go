@@ -0,0 +1,237 @@
+package rpc
+
+import (
+	"bcXwallet/common"
+	"blockchain/abciapp_v1.0/keys"
+	"blockchain/abciapp_v1.0/types"
+	"blockchain/smcsdk/sdk/std"
+	types3 "blockchain/types"
+	"container/list"
+	"math"
+	"sync"
+)
+
+const (
+	defaultMetaCacheSize = 10000         // max count of entries if metaCacheSize is not set in config
+	heightOfImmutable    = math.MaxInt64 // entry never changes, it's valid for all heights
+)
+
+// kinds of metadata, the same state key may be parsed to different types
+const (
+	metaContractV1    = "contractV1:"
+	metaContractV2    = "contractV2:"
+	metaContractVerV2 = "contractVersionsV2:"
+	metaToken         = "token:"
+	metaGenesisToken  = "genesisToken:"
+)
+
+// metaKey - key of metadata kind of state key on chain, chains may have the same state keys
+func metaKey(c *common.Chain, kind, key string) string {
+	return c.Name + "/" + kind + key
+}
+
+// metaCacheEntry - value of state key, it's valid for blocks not higher than height
+type metaCacheEntry struct {
+	key    string
+	value  interface{}
+	height int64
+}
+
+// metaCache - LRU cache of contract, contract versions and token metadata
+type metaCache struct {
+	mtx      sync.Mutex
+	capacity int
+	entries  map[string]*list.Element
+	lru      *list.List
+	hits     uint64
+	misses   uint64
+}
+
+var metadata = newMetaCache(0)
+
+func newMetaCache(capacity int) *metaCache {
+	return &metaCache{
+		capacity: capacity,
+		entries:  make(map[string]*list.Element),
+		lru:      list.New(),
+	}
+}
+
+func (c *metaCache) size() int {
+	if c.capacity > 0 {
+		return c.capacity
+	}
+
+	if size := common.GetConfig().MetaCacheSize; size > 0 {
+		return size
+	}
+
+	return defaultMetaCacheSize
+}
+
+// get - value of key for block at height, an entry that is older than height is a miss
+func (c *metaCache) get(key string, height int64) (interface{}, bool) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		if entry := elem.Value.(*metaCacheEntry); entry.height >= height {
+			c.lru.MoveToFront(elem)
+			c.hits++
+			return entry.value, true
+		}
+	}
+	c.misses++
+
+	return nil, false
+}
+
+// set - save value of key that is valid until height, return the old value
+func (c *metaCache) set(key string, value interface{}, height int64) (old interface{}) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		entry := elem.Value.(*metaCacheEntry)
+		old = entry.value
+		entry.value, entry.height = value, height
+		c.lru.MoveToFront(elem)
+		return
+	}
+
+	c.entries[key] = c.lru.PushFront(&metaCacheEntry{key: key, value: value, height: height})
+	for c.lru.Len() > c.size() {
+		last := c.lru.Back()
+		c.lru.Remove(last)
+		delete(c.entries, last.Value.(*metaCacheEntry).key)
+	}
+
+	return
+}
+
+func (c *metaCache) remove(key string) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		c.lru.Remove(elem)
+		delete(c.entries, key)
+	}
+}
+
+func (c *metaCache) stats() *MetaCacheStatsResult {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	return &MetaCacheStatsResult{Size: c.lru.Len(), Capacity: c.size(), Hits: c.hits, Misses: c.misses}
+}
+
+// contractV2 - contract of address, a contract without lose height may be upgraded later,
+// so it's only valid for blocks not higher than the height its state is read at
+func contractV2(c *common.Chain, contractAddress types3.Address, height int64) (*std.Contract, error) {
+	key := std.KeyOfContract(contractAddress)
+	if value, ok := metadata.get(metaKey(c, metaContractV2, key), height); ok {
+		return value.(*std.Contract), nil
+	}
+
+	validHeight, err := stateHeight(c)
+	if err != nil {
+		return nil, err
+	}
+
+	contract := new(std.Contract)
+	if err := common.DoHttpQueryAndParse(c, key, contract); err != nil {
+		return nil, err
+	}
+
+	if contract.LoseHeight != 0 {
+		validHeight = heightOfImmutable
+	}
+
+	// lose height is set when a new version is deployed, versions of the contract must be queried again
+	if old := metadata.set(metaKey(c, metaContractV2, key), contract, validHeight); old != nil && old.(*std.Contract).LoseHeight != contract.LoseHeight {
+		metadata.remove(metaKey(c, metaContractVerV2, std.KeyOfContractsWithName(contract.OrgID, contract.Name)))
+	}
+
+	return contract, nil
+}
+
+// contractVersionsV2 - versions of contract, versions effective at height are all deployed before it
+func contractVersionsV2(c *common.Chain, orgID, name string, height int64) (*std.ContractVersionList, error) {
+	key := std.KeyOfContractsWithName(orgID, name)
+	if value, ok := metadata.get(metaKey(c, metaContractVerV2, key), height); ok {
+		return value.(*std.ContractVersionList), nil
+	}
+
+	validHeight, err := stateHeight(c)
+	if err != nil {
+		return nil, err
+	}
+
+	conVer := new(std.ContractVersionList)
+	if err := common.DoHttpQueryAndParse(c, key, conVer); err != nil {
+		return nil, err
+	}
+	metadata.set(metaKey(c, metaContractVerV2, key), conVer, validHeight)
+
+	return conVer, nil
+}
+
+// stateHeight - current block height got before the latest state is queried, the state is valid up to it,
+// height of the caller is not used since it's not checked with the chain
+func stateHeight(c *common.Chain) (int64, error) {
+	blkHeight, err := blockHeight(c)
+	if err != nil {
+		return 0, err
+	}
+
+	return blkHeight.LastBlock, nil
+}
+
+// contractV1 - contract of chain version 1, it's never upgraded
+func contractV1(c *common.Chain, contractAddress keys.Address) (*types.Contract, error) {
+	key := keyOfContract(contractAddress)
+	if value, ok := metadata.get(metaKey(c, metaContractV1, key), 0); ok {
+		return value.(*types.Contract), nil
+	}
+
+	contract := new(types.Contract)
+	if err := common.DoHttpQueryAndParse(c, key, contract); err != nil {
+		return nil, err
+	}
+	metadata.set(metaKey(c, metaContractV1, key), contract, heightOfImmutable)
+
+	return contract, nil
+}
+
+// tokenOf - token of address, its name never changes
+func tokenOf(c *common.Chain, tokenAddress keys.Address) (*types.IssueToken, error) {
+	key := keyOfToken(tokenAddress)
+	if value, ok := metadata.get(metaKey(c, metaToken, key), 0); ok {
+		return value.(*types.IssueToken), nil
+	}
+
+	token := new(types.IssueToken)
+	if err := common.DoHttpQueryAndParse(c, key, token); err != nil {
+		return nil, err
+	}
+	metadata.set(metaKey(c, metaToken, key), token, heightOfImmutable)
+
+	return token, nil
+}
+
+// genesisTokenOf - genesis token of chain
+func genesisTokenOf(c *common.Chain) (*types.IssueToken, error) {
+	key := keyOfGenesisToken()
+	if value, ok := metadata.get(metaKey(c, metaGenesisToken, key), 0); ok {
+		return value.(*types.IssueToken), nil
+	}
+
+	token := new(types.IssueToken)
+	if err := common.DoHttpQueryAndParse(c, key, token); err != nil {
+		return nil, err
+	}
+	metadata.set(metaKey(c, metaGenesisToken, key), token, heightOfImmutable)
+
+	return token, nil
+}
@@ -0,0 +1,92 @@
+package rpc
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"bcXwallet/common"
+	"bcXwallet/common/config"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetaCache(t *testing.T) {
+	assert := assert.New(t)
+
+	cache := newMetaCache(2)
+
+	_, ok := cache.get("a", 10)
+	assert.False(ok)
+
+	assert.Nil(cache.set("a", "va", 10))
+
+	// entry is valid for blocks not higher than its height
+	value, ok := cache.get("a", 10)
+	assert.True(ok)
+	assert.Equal("va", value)
+	_, ok = cache.get("a", 11)
+	assert.False(ok)
+
+	assert.Equal("va", cache.set("a", "va2", heightOfImmutable))
+	value, ok = cache.get("a", 1000)
+	assert.True(ok)
+	assert.Equal("va2", value)
+
+	// the least recently used entry is evicted
+	cache.set("b", "vb", heightOfImmutable)
+	cache.get("a", 0)
+	cache.set("c", "vc", heightOfImmutable)
+	_, ok = cache.get("b", 0)
+	assert.False(ok)
+	_, ok = cache.get("a", 0)
+	assert.True(ok)
+
+	cache.remove("a")
+	_, ok = cache.get("a", 0)
+	assert.False(ok)
+
+	stats := cache.stats()
+	assert.Equal(1, stats.Size)
+	assert.Equal(2, stats.Capacity)
+	assert.Equal(uint64(4), stats.Hits)
+	assert.Equal(uint64(4), stats.Misses)
+}
+
+func TestContractV2ValidHeight(t *testing.T) {
+	assert := assert.New(t)
+
+	// the node is at height 100, the contract has no new version
+	node := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string `json:"method"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Method == "abci_info" {
+			w.Write([]byte(`{"jsonrpc":"2.0","id":"jsonrpc-client","result":{"response":{"last_block_height":100}}}`))
+			return
+		}
+		value := base64.StdEncoding.EncodeToString([]byte(`{"address":"bcbContract","name":"token","version":"1.0"}`))
+		w.Write([]byte(`{"jsonrpc":"2.0","id":"jsonrpc-client","result":{"response":{"code":200,"value":"` + value + `","height":100}}}`))
+	}))
+	defer node.Close()
+	c := common.NewChain(config.ChainProfile{Name: "contractV2", ChainID: testChainID, ChainVersion: "2", NodeAddrSlice: []string{node.URL}})
+
+	_, err := latestHeight(c, 101)
+	assert.NotNil(err)
+	height, err := latestHeight(c, 0)
+	assert.Nil(err)
+	assert.Equal(int64(100), height)
+
+	// the contract is cached up to height of the node, not height of the caller
+	contract, err := contractV2(c, "bcbContract", 5000)
+	assert.Nil(err)
+	assert.Equal("token", contract.Name)
+	key := metaKey(c, metaContractV2, "/contract/bcbContract")
+	_, ok := metadata.get(key, 100)
+	assert.True(ok)
+	_, ok = metadata.get(key, 101)
+	assert.False(ok)
+}
@@ -0,0 +1,386 @@
+package rpc
+
+import (
+	"bcXwallet/common"
+	"blockchain/abciapp_v1.0/keys"
+	"blockchain/smcsdk/sdk/bn"
+	"blockchain/smcsdk/sdk/rlp"
+	"blockchain/tx2"
+	types3 "blockchain/types"
+	"common/sig"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/btcsuite/btcutil/base58"
+	tmtypes "github.com/tendermint/tendermint/types"
+)
+
+// multisigID - id of multi-signature transaction is the hash of payload
+func multisigID(payload []byte) string {
+	hash := sha256.Sum256(payload)
+	return hex.EncodeToString(hash[:])
+}
+
+func checkMultisigChainVersion(c *common.Chain) error {
+	chainVersion, err := c.ChainVersion()
+	if err != nil {
+		return err
+	}
+
+	if chainVersion != "2" {
+		return errors.New("Multi-signature transaction needs chainVersion 2 ")
+	}
+
+	return nil
+}
+
+// checkSigners - signers cannot be empty or duplicate, threshold must be in [1, count of signers]
+func checkSigners(c *common.Chain, signers []keys.Address, threshold int) error {
+	if len(signers) == 0 {
+		return errors.New("Signers cannot be empty ")
+	}
+
+	for i, signer := range signers {
+		if err := checkAddress(c.ChainID, signer); err != nil {
+			return err
+		}
+		for _, other := range signers[:i] {
+			if other == signer {
+				return errors.New("Duplicate signer " + signer)
+			}
+		}
+	}
+
+	if threshold < 1 || threshold > len(signers) {
+		return fmt.Errorf("Threshold must be [1-%d] ", len(signers))
+	}
+
+	return nil
+}
+
+func multisigCreate(c *common.Chain, gasLimit uint64, value *big.Int, walletParams MultisigParam) (result *MultisigTxResult, err error) {
+
+	if err = checkMultisigChainVersion(c); err != nil {
+		return
+	}
+
+	// the first signer is the sender of transaction
+	nonceValue := walletParams.Nonce
+	if nonceValue == 0 {
+		var nonceResult *NonceResult
+		if nonceResult, err = nonce(c, walletParams.Signers[0], 0); err != nil {
+			return
+		}
+		nonceValue = nonceResult.Nonce
+	}
+
+	message := types3.Message{
+		Contract: walletParams.SmcAddress,
+		MethodID: 0x44D8CA60,
+		Items:    tx2.WrapInvokeParams(walletParams.To, bn.NString(value.String())),
+	}
+	payload := tx2.WrapPayload(nonceValue, int64(gasLimit), walletParams.Note, message)
+
+	if result, err = newMultisigTx(c, payload, walletParams.Signers, walletParams.Threshold); err != nil {
+		return
+	}
+
+	err = db.SetMultisigTx(result)
+
+	return
+}
+
+// newMultisigTx - make multi-signature transaction document without signature, the readable fields are decoded from payload
+func newMultisigTx(c *common.Chain, payload []byte, signers []keys.Address, threshold int) (result *MultisigTxResult, err error) {
+
+	var transaction types3.Transaction
+	if err = rlp.DecodeBytes(payload, &transaction); err != nil {
+		return
+	}
+
+	result = &MultisigTxResult{
+		ID:         multisigID(payload),
+		ChainID:    c.ChainID,
+		Payload:    base58.Encode(payload),
+		Nonce:      transaction.Nonce,
+		GasLimit:   transaction.GasLimit,
+		Note:       transaction.Note,
+		Messages:   make([]Message, 0),
+		Signers:    signers,
+		Threshold:  threshold,
+		Signatures: make([]MultisigSignature, 0),
+	}
+
+	for _, message := range transaction.Messages {
+		msg := Message{
+			SmcAddress: message.Contract,
+			Method:     fmt.Sprintf("%x", message.MethodID),
+		}
+
+		if msg.Method == transferMethodIDV2 && len(message.Items) == 2 {
+			var to types3.Address
+			if err = rlp.DecodeBytes(message.Items[0], &to); err != nil {
+				return nil, err
+			}
+
+			var value bn.Number
+			if err = rlp.DecodeBytes(message.Items[1], &value); err != nil {
+				return nil, err
+			}
+			msg.To = to
+			msg.Value = value.String()
+		}
+
+		result.Messages = append(result.Messages, msg)
+	}
+
+	return
+}
+
+func multisigSign(c *common.Chain, name, accessKey, id string) (result *MultisigTxResult, err error) {
+
+	if result, err = existMultisigTx(c, id); err != nil {
+		return
+	}
+
+	acct, err := chainAccount(c, name, base58.Decode(accessKey))
+	if err != nil {
+		return
+	}
+
+	if indexOfSigner(result.Signers, acct.Address) < 0 {
+		return nil, errors.New("The wallet of " + name + " is not signer of multi-signature transaction ")
+	}
+
+	sigInfo := tx2.SignPayload(base58.Decode(result.Payload), "0x"+hex.EncodeToString(acct.PrivateKey))
+	setSignature(result, MultisigSignature{
+		Signer:    acct.Address,
+		PubKey:    hex.EncodeToString(sigInfo.PubKey[:]),
+		Signature: hex.EncodeToString(sigInfo.SigValue[:]),
+	})
+
+	err = db.SetMultisigTx(result)
+
+	return
+}
+
+func multisigSigners(c *common.Chain, id string) (result *MultisigSignersResult, err error) {
+
+	multisigTx, err := existMultisigTx(c, id)
+	if err != nil {
+		return
+	}
+
+	result = &MultisigSignersResult{
+		ID:        multisigTx.ID,
+		Threshold: multisigTx.Threshold,
+		Signed:    make([]keys.Address, 0),
+		Unsigned:  make([]keys.Address, 0),
+	}
+	for _, signer := range multisigTx.Signers {
+		if indexOfSignature(multisigTx.Signatures, signer) >= 0 {
+			result.Signed = append(result.Signed, signer)
+		} else {
+			result.Unsigned = append(result.Unsigned, signer)
+		}
+	}
+	result.Ready = len(result.Signed) >= multisigTx.Threshold &&
+		indexOfSignature(multisigTx.Signatures, multisigTx.Signers[0]) >= 0
+
+	return
+}
+
+func multisigExport(c *common.Chain, id string) (result *MultisigTxResult, err error) {
+	return existMultisigTx(c, id)
+}
+
+// multisigImport - merge the signatures of document signed on other wallet, every signature is verified
+func multisigImport(c *common.Chain, data string) (result *MultisigTxResult, err error) {
+
+	if err = checkMultisigChainVersion(c); err != nil {
+		return
+	}
+
+	imported := new(MultisigTxResult)
+	if err = cdc.UnmarshalJSON([]byte(data), imported); err != nil {
+		return nil, errors.New("The format of multi-signature transaction is wrong: " + err.Error())
+	}
+
+	if imported.ChainID != c.ChainID {
+		return nil, errors.New("ChainID of multi-signature transaction is " + imported.ChainID + ", expected " + c.ChainID)
+	}
+
+	payload := base58.Decode(imported.Payload)
+	if imported.ID != multisigID(payload) {
+		return nil, errors.New("ID does not match the payload of multi-signature transaction ")
+	}
+
+	if result, err = db.MultisigTx(imported.ID); err != nil {
+		return
+	}
+
+	if result == nil {
+		if err = checkSigners(c, imported.Signers, imported.Threshold); err != nil {
+			return
+		}
+		// never trust the readable fields of imported document
+		if result, err = newMultisigTx(c, payload, imported.Signers, imported.Threshold); err != nil {
+			return
+		}
+	} else if !sameSigners(result, imported) {
+		return nil, errors.New("Signers or threshold of multi-signature transaction does not match ")
+	}
+
+	for _, signature := range imported.Signatures {
+		if err = verifySignature(c, result, payload, signature); err != nil {
+			return nil, err
+		}
+		setSignature(result, signature)
+	}
+
+	err = db.SetMultisigTx(result)
+
+	return
+}
+
+func multisigFinalize(c *common.Chain, id string) (result *MultisigFinalizeResult, err error) {
+
+	multisigTx, err := existMultisigTx(c, id)
+	if err != nil {
+		return
+	}
+
+	if indexOfSignature(multisigTx.Signatures, multisigTx.Signers[0]) < 0 {
+		return nil, errors.New("The sender " + multisigTx.Signers[0] + " has not signed yet ")
+	}
+
+	// signatures are ordered by signers, so the sender is always the first one
+	sigInfos := make([]sig.Ed25519Sig, 0, len(multisigTx.Signatures))
+	for _, signer := range multisigTx.Signers {
+		index := indexOfSignature(multisigTx.Signatures, signer)
+		if index < 0 {
+			continue
+		}
+
+		var sigInfo sig.Ed25519Sig
+		if sigInfo, err = decodeSignature(multisigTx.Signatures[index]); err != nil {
+			return
+		}
+		sigInfos = append(sigInfos, sigInfo)
+	}
+
+	if len(sigInfos) < multisigTx.Threshold {
+		return nil, fmt.Errorf("Need %d signatures, only %d collected ", multisigTx.Threshold, len(sigInfos))
+	}
+
+	result = new(MultisigFinalizeResult)
+	result.Tx = tx2.WrapMultiSigTxOfChain(c.ChainID, base58.Decode(multisigTx.Payload), sigInfos...)
+	result.TxHash = "0x" + hex.EncodeToString(tmtypes.Tx(result.Tx).Hash())
+
+	return
+}
+
+func existMultisigTx(c *common.Chain, id string) (*MultisigTxResult, error) {
+	multisigTx, err := db.MultisigTx(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if multisigTx == nil {
+		return nil, errors.New("Multi-signature transaction " + id + " does not exist ")
+	}
+
+	if multisigTx.ChainID != c.ChainID {
+		return nil, errors.New("Multi-signature transaction " + id + " is of chain ID " + multisigTx.ChainID + " ")
+	}
+
+	return multisigTx, nil
+}
+
+func decodeSignature(signature MultisigSignature) (sigInfo sig.Ed25519Sig, err error) {
+
+	pubKey, err := hex.DecodeString(signature.PubKey)
+	if err != nil || len(pubKey) != len(sigInfo.PubKey) {
+		return sigInfo, errors.New("The format of pubKey is wrong ")
+	}
+
+	sigValue, err := hex.DecodeString(signature.Signature)
+	if err != nil || len(sigValue) != len(sigInfo.SigValue) {
+		return sigInfo, errors.New("The format of signature is wrong ")
+	}
+
+	sigInfo.SigType = "ed25519"
+	copy(sigInfo.PubKey[:], pubKey)
+	copy(sigInfo.SigValue[:], sigValue)
+
+	return sigInfo, nil
+}
+
+// verifySignature - signature must be made by the declared signer on payload
+func verifySignature(c *common.Chain, multisigTx *MultisigTxResult, payload []byte, signature MultisigSignature) error {
+
+	if indexOfSigner(multisigTx.Signers, signature.Signer) < 0 {
+		return errors.New(signature.Signer + " is not signer of multi-signature transaction ")
+	}
+
+	sigInfo, err := decodeSignature(signature)
+	if err != nil {
+		return err
+	}
+
+	if sigInfo.PubKey.Address(c.ChainID) != signature.Signer {
+		return errors.New("PubKey does not match signer " + signature.Signer)
+	}
+
+	if !sigInfo.PubKey.VerifyBytes(payload, sigInfo.SigValue) {
+		return errors.New("Verify signature of " + signature.Signer + " failed ")
+	}
+
+	return nil
+}
+
+func setSignature(multisigTx *MultisigTxResult, signature MultisigSignature) {
+	if index := indexOfSignature(multisigTx.Signatures, signature.Signer); index >= 0 {
+		multisigTx.Signatures[index] = signature
+		return
+	}
+
+	multisigTx.Signatures = append(multisigTx.Signatures, signature)
+}
+
+func sameSigners(a, b *MultisigTxResult) bool {
+	if a.Threshold != b.Threshold || len(a.Signers) != len(b.Signers) {
+		return false
+	}
+
+	for i := range a.Signers {
+		if a.Signers[i] != b.Signers[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func indexOfSigner(signers []keys.Address, address keys.Address) int {
+	for i, signer := range signers {
+		if signer == address {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func indexOfSignature(signatures []MultisigSignature, address keys.Address) int {
+	for i, signature := range signatures {
+		if signature.Signer == address {
+			return i
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,86 @@
+package rpc
+
+import (
+	"blockchain/abciapp_v1.0/keys"
+	"blockchain/smcsdk/sdk/bn"
+	"blockchain/tx2"
+	"blockchain/types"
+	"encoding/hex"
+	"testing"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/stretchr/testify/assert"
+	"github.com/tendermint/go-crypto"
+)
+
+func signMultisigTx(multisigTx *MultisigTxResult, privKey string) MultisigSignature {
+	sigInfo := tx2.SignPayload(base58.Decode(multisigTx.Payload), privKey)
+
+	return MultisigSignature{
+		Signer:    sigInfo.PubKey.Address(testChainID),
+		PubKey:    hex.EncodeToString(sigInfo.PubKey[:]),
+		Signature: hex.EncodeToString(sigInfo.SigValue[:]),
+	}
+}
+
+func TestMultisigFinalize(t *testing.T) {
+	assert := assert.New(t)
+	defer openTestDB(t)()
+	tx2.Init(testChainID)
+	crypto.SetChainId(testChainID)
+
+	privKeyBytes, _ := hex.DecodeString(testPrivKey[2:])
+	sender := crypto.PrivKeyEd25519FromBytes(privKeyBytes).PubKey().Address(testChainID)
+	privKey2 := crypto.GenPrivKeyEd25519()
+	privKey3 := crypto.GenPrivKeyEd25519()
+	signers := []keys.Address{sender, privKey2.PubKey().Address(testChainID), privKey3.PubKey().Address(testChainID)}
+
+	message := types.Message{
+		Contract: testAddress,
+		MethodID: 0x44D8CA60,
+		Items:    tx2.WrapInvokeParams(testAddress, bn.N(1000)),
+	}
+	payload := tx2.WrapPayload(1, 500, "multisig", message)
+
+	multisigTx, err := newMultisigTx(testChain, payload, signers, 2)
+	if !assert.Nil(err) {
+		return
+	}
+	assert.Equal("1000", multisigTx.Messages[0].Value)
+	assert.Nil(db.SetMultisigTx(multisigTx))
+
+	// signature of sender is required
+	setSignature(multisigTx, signMultisigTx(multisigTx, "0x"+hex.EncodeToString(privKey3[:])))
+	assert.Nil(db.SetMultisigTx(multisigTx))
+	_, err = multisigFinalize(testChain, multisigTx.ID)
+	assert.NotNil(err)
+
+	// signature of other payload is rejected
+	other, _ := newMultisigTx(testChain, tx2.WrapPayload(2, 500, "multisig", message), signers, 2)
+	assert.NotNil(verifySignature(testChain, multisigTx, payload, signMultisigTx(other, testPrivKey)))
+
+	signature := signMultisigTx(multisigTx, testPrivKey)
+	assert.Nil(verifySignature(testChain, multisigTx, payload, signature))
+	setSignature(multisigTx, signature)
+	assert.Nil(db.SetMultisigTx(multisigTx))
+
+	signersResult, err := multisigSigners(testChain, multisigTx.ID)
+	if assert.Nil(err) {
+		assert.True(signersResult.Ready)
+		assert.Equal([]keys.Address{signers[1]}, signersResult.Unsigned)
+	}
+
+	result, err := multisigFinalize(testChain, multisigTx.ID)
+	if !assert.Nil(err) {
+		return
+	}
+
+	transaction, pubKeys, err := tx2.TxParseMultiSig(result.Tx)
+	if assert.Nil(err) {
+		assert.Equal(uint64(1), transaction.Nonce)
+		if assert.Equal(2, len(pubKeys)) {
+			assert.Equal(sender, pubKeys[0].Address(testChainID))
+			assert.Equal(signers[2], pubKeys[1].Address(testChainID))
+		}
+	}
+}
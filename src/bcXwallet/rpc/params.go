@@ -0,0 +1,205 @@
+package rpc
+
+import (
+	"blockchain/smcsdk/sdk/bn"
+	"blockchain/smcsdk/sdk/rlp"
+	"common/bignumber_v1.0"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+
+	cmn "github.com/tendermint/tmlibs/common"
+)
+
+var (
+	typeOfBytes  = reflect.TypeOf([]byte{})
+	typeOfNumber = reflect.TypeOf(bn.Number{})
+)
+
+// basicTypesV2 - go types of contract method parameters on chain version 2
+var basicTypesV2 = map[string]reflect.Type{
+	"types.Address":  reflect.TypeOf(""),
+	"string":         reflect.TypeOf(""),
+	"bn.Number":      typeOfNumber,
+	"types.Hash":     typeOfBytes,
+	"types.HexBytes": typeOfBytes,
+	"types.PubKey":   typeOfBytes,
+	"bool":           reflect.TypeOf(false),
+	"int":            reflect.TypeOf(int(0)),
+	"int8":           reflect.TypeOf(int8(0)),
+	"int16":          reflect.TypeOf(int16(0)),
+	"int32":          reflect.TypeOf(int32(0)),
+	"int64":          reflect.TypeOf(int64(0)),
+	"uint":           reflect.TypeOf(uint(0)),
+	"uint8":          reflect.TypeOf(uint8(0)),
+	"byte":           reflect.TypeOf(byte(0)),
+	"uint16":         reflect.TypeOf(uint16(0)),
+	"uint32":         reflect.TypeOf(uint32(0)),
+	"uint64":         reflect.TypeOf(uint64(0)),
+}
+
+// paramTypes - parse types of parameters from method prototype, e.g. Transfer(types.Address,bn.Number)
+func paramTypes(prototype string) []string {
+	start := strings.Index(prototype, "(")
+	if start < 0 {
+		return nil
+	}
+
+	types := make([]string, 0)
+	depth := 0
+	begin := start + 1
+	for i := begin; i < len(prototype); i++ {
+		switch prototype[i] {
+		case '(', '[':
+			depth++
+		case ']':
+			depth--
+		case ')':
+			if depth == 0 {
+				if t := strings.TrimSpace(prototype[begin:i]); t != "" {
+					types = append(types, t)
+				}
+				return types
+			}
+			depth--
+		case ',':
+			if depth == 0 {
+				types = append(types, strings.TrimSpace(prototype[begin:i]))
+				begin = i + 1
+			}
+		}
+	}
+
+	return types
+}
+
+// goTypeV2 - go type of parameter type in prototype, include slice and map of known types
+func goTypeV2(typeStr string) (reflect.Type, bool) {
+	if t, ok := basicTypesV2[typeStr]; ok {
+		return t, true
+	}
+
+	if strings.HasPrefix(typeStr, "[]") {
+		elem, ok := goTypeV2(typeStr[2:])
+		if !ok {
+			return nil, false
+		}
+		return reflect.SliceOf(elem), true
+	}
+
+	if strings.HasPrefix(typeStr, "map[") {
+		end := strings.Index(typeStr, "]")
+		if end < 0 {
+			return nil, false
+		}
+		key, ok := goTypeV2(typeStr[4:end])
+		if !ok || key.Kind() == reflect.Slice || key == typeOfNumber {
+			return nil, false
+		}
+		elem, ok := goTypeV2(typeStr[end+1:])
+		if !ok {
+			return nil, false
+		}
+		return reflect.MapOf(key, elem), true
+	}
+
+	return nil, false
+}
+
+// decodeParamsV2 - decode RLP items of message with types in prototype, unknown types fall back to hex
+func decodeParamsV2(prototype string, items []cmn.HexBytes) []Param {
+	types := paramTypes(prototype)
+
+	params := make([]Param, 0, len(items))
+	for i, item := range items {
+		param := Param{Value: hex.EncodeToString(item)}
+		if i < len(types) {
+			param.Type = types[i]
+			if t, ok := goTypeV2(param.Type); ok {
+				v := reflect.New(t)
+				if err := rlp.DecodeBytes(item, v.Interface()); err == nil {
+					param.Value = formatParam(v.Elem())
+				}
+			}
+		}
+		params = append(params, param)
+	}
+
+	return params
+}
+
+// decodeParamsV1 - decode raw items of message with types in prototype, unknown types fall back to hex
+func decodeParamsV1(prototype string, items [][]byte) []Param {
+	types := paramTypes(prototype)
+
+	params := make([]Param, 0, len(items))
+	for i, item := range items {
+		param := Param{Value: hex.EncodeToString(item)}
+		if i < len(types) {
+			param.Type = types[i]
+			switch param.Type {
+			case "smc.Address", "string":
+				param.Value = string(item)
+			case "big.Int", "*big.Int", "bn.Number":
+				param.Value = bignumber.SetBytes(item).String()
+			case "uint64", "uint32", "uint16", "uint8", "uint":
+				if len(item) <= 8 {
+					param.Value = new(big.Int).SetBytes(item).String()
+				}
+			case "bool":
+				if len(item) == 1 {
+					param.Value = fmt.Sprint(item[0] != 0)
+				}
+			}
+		}
+		params = append(params, param)
+	}
+
+	return params
+}
+
+// formatParam - format decoded value, composite value is formatted to JSON text
+func formatParam(v reflect.Value) string {
+	switch value := displayValue(v).(type) {
+	case string:
+		return value
+	case []interface{}, map[string]interface{}:
+		data, err := json.Marshal(value)
+		if err != nil {
+			return ""
+		}
+		return string(data)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// displayValue - convert decoded value to readable value, bn.Number to decimal string and bytes to hex
+func displayValue(v reflect.Value) interface{} {
+	if v.Type() == typeOfNumber {
+		return numberString(v.Interface().(bn.Number))
+	}
+
+	switch v.Kind() {
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return hex.EncodeToString(v.Bytes())
+		}
+		list := make([]interface{}, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			list = append(list, displayValue(v.Index(i)))
+		}
+		return list
+	case reflect.Map:
+		m := make(map[string]interface{}, v.Len())
+		for _, key := range v.MapKeys() {
+			m[fmt.Sprint(key.Interface())] = displayValue(v.MapIndex(key))
+		}
+		return m
+	default:
+		return v.Interface()
+	}
+}
@@ -0,0 +1,65 @@
+package rpc
+
+import (
+	"blockchain/smcsdk/sdk/bn"
+	"blockchain/tx2"
+	"common/bignumber_v1.0"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParamTypes(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal([]string{"types.Address", "bn.Number"}, paramTypes("Transfer(types.Address,bn.Number)"))
+	assert.Equal([]string{"map[string]bn.Number", "[]types.Address"}, paramTypes("Set(map[string]bn.Number, []types.Address)"))
+	assert.Equal([]string{}, paramTypes("Init()"))
+	assert.Nil(paramTypes(""))
+}
+
+func TestDecodeParamsV2(t *testing.T) {
+	assert := assert.New(t)
+
+	items := tx2.WrapInvokeParams(
+		testAddress,
+		bn.NString("18446744073709551616"),
+		uint64(7),
+		true,
+		[]byte{0x01, 0x02},
+		[]string{"a", "b"},
+		map[string]bn.Number{"x": bn.N(5)},
+		"unknown",
+	)
+	prototype := "Call(types.Address,bn.Number,uint64,bool,types.HexBytes,[]string,map[string]bn.Number,unknown.Type)"
+
+	params := decodeParamsV2(prototype, items)
+	assert.Equal([]Param{
+		{"types.Address", testAddress},
+		{"bn.Number", "18446744073709551616"},
+		{"uint64", "7"},
+		{"bool", "true"},
+		{"types.HexBytes", "0102"},
+		{"[]string", `["a","b"]`},
+		{"map[string]bn.Number", `{"x":"5"}`},
+		{"unknown.Type", "87756e6b6e6f776e"},
+	}, params)
+
+	// items without prototype are kept in hex
+	params = decodeParamsV2("", items[:1])
+	assert.Equal("", params[0].Type)
+}
+
+func TestDecodeParamsV1(t *testing.T) {
+	assert := assert.New(t)
+
+	items := [][]byte{[]byte(testAddress), bignumber.NB(big.NewInt(1000)).Bytes(), {0x01, 0x00}, {0xff}}
+	params := decodeParamsV1("Transfer(smc.Address,big.Int,uint64,smc.Hash)", items)
+	assert.Equal([]Param{
+		{"smc.Address", testAddress},
+		{"big.Int", "1000"},
+		{"uint64", "256"},
+		{"smc.Hash", "ff"},
+	}, params)
+}
@@ -0,0 +1,69 @@
+package rpc
+
+import (
+	"bcXwallet/common"
+	"blockchain/tx2"
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/btcsuite/btcutil/base58"
+)
+
+// InitQuerySigner - sign all queries of every chain with the wallet of its queryWallet in config
+func InitQuerySigner() error {
+	for _, c := range common.Chains() {
+		if c.QueryWallet == "" {
+			continue
+		}
+
+		signer, err := walletQuerySigner(c, c.QueryWallet, c.QueryAccessKey)
+		if err != nil {
+			return err
+		}
+		c.SetQuerySigner(signer)
+	}
+
+	return nil
+}
+
+// walletQuerySigner - make signer of <qy> envelope of chain with wallet
+func walletQuerySigner(c *common.Chain, name, accessKey string) (common.QuerySigner, error) {
+
+	acct, err := chainAccount(c, name, base58.Decode(accessKey))
+	if err != nil {
+		return nil, err
+	}
+	privKey := "0x" + hex.EncodeToString(acct.PrivateKey)
+
+	return func(key string) (string, error) {
+		return tx2.WrapQueryOfChain(c.ChainID, key, privKey), nil
+	}, nil
+}
+
+func signedQuery(c *common.Chain, name, accessKey, key string) (result *SignedQueryResult, err error) {
+
+	signer, err := walletQuerySigner(c, name, accessKey)
+	if err != nil {
+		return
+	}
+
+	value, err := common.DoHttpQueryWithSigner(c, key, signer)
+	if err != nil {
+		return
+	}
+
+	return newSignedQueryResult(key, value), nil
+}
+
+// newSignedQueryResult - value in hex, and decoded data if the value is JSON
+func newSignedQueryResult(key string, value []byte) *SignedQueryResult {
+	result := &SignedQueryResult{Key: key, Value: hex.EncodeToString(value)}
+
+	var data bytes.Buffer
+	if json.Compact(&data, value) == nil {
+		result.Data = json.RawMessage(data.Bytes())
+	}
+
+	return result
+}
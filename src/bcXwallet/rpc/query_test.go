@@ -0,0 +1,41 @@
+package rpc
+
+import (
+	tx3 "blockchain/abciapp_v1.0/tx/tx"
+	"blockchain/tx2"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tendermint/go-amino"
+	"github.com/tendermint/go-crypto"
+)
+
+func TestWrapQuery(t *testing.T) {
+	assert := assert.New(t)
+	tx2.Init(testChainID)
+
+	qyStr := tx2.WrapQuery("/token/all/0", testPrivKey)
+
+	qy := new(tx3.Query)
+	address, err := qy.QueryDataParse(testChainID, qyStr)
+	if assert.Nil(err) {
+		privKeyBytes, _ := hex.DecodeString(testPrivKey[2:])
+		assert.Equal(crypto.PrivKeyEd25519FromBytes(privKeyBytes).PubKey().Address(testChainID), address)
+		assert.Equal("/token/all/0", qy.QueryKey)
+	}
+}
+
+func TestSignedQueryResult(t *testing.T) {
+	assert := assert.New(t)
+	cdc := amino.NewCodec()
+
+	// data of JSON value is a JSON value in response
+	js, err := cdc.MarshalJSON(newSignedQueryResult("/account/ex/a", []byte(`{ "nonce": 2 }`)))
+	assert.Nil(err)
+	assert.Equal(`{"key":"/account/ex/a","value":"7b20226e6f6e6365223a2032207d","data":{"nonce":2}}`, string(js))
+
+	js, err = cdc.MarshalJSON(newSignedQueryResult("/raw", []byte{1, 2}))
+	assert.Nil(err)
+	assert.Equal(`{"key":"/raw","value":"0102"}`, string(js))
+}
@@ -0,0 +1,109 @@
+package rpc
+
+import (
+	"blockchain/smcsdk/sdk/bn"
+	"blockchain/smcsdk/sdk/crypto/sha3"
+	"blockchain/smcsdk/sdk/std"
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strconv"
+
+	cmn "github.com/tendermint/tmlibs/common"
+)
+
+// names of standard receipts
+const (
+	receiptTransfer    = "std::transfer"
+	receiptFee         = "std::fee"
+	receiptBurn        = "std::burn"
+	receiptAddSupply   = "std::addSupply"
+	receiptSetOwner    = "std::setOwner"
+	receiptSetGasPrice = "std::setGasPrice"
+)
+
+var (
+	errReceiptHash    = errors.New("receipt hash is wrong")
+	errReceiptUnknown = errors.New("receipt name is unknown")
+)
+
+// receipts - decode receipts in tags of DeliverTx result, the order is the same as emitted
+func receipts(tags []cmn.KVPair) []Receipt {
+	result := make([]Receipt, 0)
+
+	for _, tag := range tags {
+		var rpt std.Receipt
+		if err := json.Unmarshal(tag.Value, &rpt); err != nil || rpt.Name == "" {
+			continue
+		}
+
+		result = append(result, decodeReceipt(rpt))
+	}
+
+	return result
+}
+
+// decodeReceipt - decode standard receipt to typed fields, others keep raw bytes and hash
+func decodeReceipt(rpt std.Receipt) Receipt {
+	receipt := Receipt{Name: rpt.Name, ContractAddr: rpt.ContractAddr}
+
+	var err error
+	if !bytes.Equal(sha3.Sum256([]byte(rpt.Name), []byte(rpt.ContractAddr), rpt.Bytes), rpt.Hash) {
+		err = errReceiptHash
+	} else {
+		switch rpt.Name {
+		case receiptTransfer:
+			var v std.Transfer
+			if err = json.Unmarshal(rpt.Bytes, &v); err == nil {
+				receipt.Token, receipt.From, receipt.To, receipt.Value = v.Token, v.From, v.To, numberString(v.Value)
+			}
+		case receiptFee:
+			var v std.Fee
+			if err = json.Unmarshal(rpt.Bytes, &v); err == nil {
+				receipt.Token, receipt.From, receipt.Value = v.Token, v.From, strconv.FormatInt(v.Value, 10)
+			}
+		case receiptBurn:
+			var v std.Burn
+			if err = json.Unmarshal(rpt.Bytes, &v); err == nil {
+				receipt.Token, receipt.Value, receipt.TotalSupply = v.Token, numberString(v.Value), numberString(v.TotalSupply)
+			}
+		case receiptAddSupply:
+			var v std.AddSupply
+			if err = json.Unmarshal(rpt.Bytes, &v); err == nil {
+				receipt.Token, receipt.Value, receipt.TotalSupply = v.Token, numberString(v.Value), numberString(v.TotalSupply)
+			}
+		case receiptSetOwner:
+			var v std.SetOwner
+			if err = json.Unmarshal(rpt.Bytes, &v); err == nil {
+				receipt.SmcAddress, receipt.NewOwner = v.ContractAddr, v.NewOwner
+			}
+		case receiptSetGasPrice:
+			var v std.SetGasPrice
+			if err = json.Unmarshal(rpt.Bytes, &v); err == nil {
+				receipt.Token, receipt.GasPrice = v.Token, strconv.FormatInt(v.GasPrice, 10)
+			}
+		default:
+			err = errReceiptUnknown
+		}
+	}
+
+	if err != nil {
+		receipt = Receipt{
+			Name:         rpt.Name,
+			ContractAddr: rpt.ContractAddr,
+			Bytes:        hex.EncodeToString(rpt.Bytes),
+			Hash:         hex.EncodeToString(rpt.Hash),
+		}
+	}
+
+	return receipt
+}
+
+func numberString(n bn.Number) string {
+	if n.V == nil {
+		return "0"
+	}
+
+	return n.String()
+}
@@ -0,0 +1,66 @@
+package rpc
+
+import (
+	"blockchain/smcsdk/sdk/bn"
+	"blockchain/smcsdk/sdk/crypto/sha3"
+	"blockchain/smcsdk/sdk/std"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	cmn "github.com/tendermint/tmlibs/common"
+)
+
+func receiptTag(t *testing.T, index int, name string, receipt interface{}) cmn.KVPair {
+	bz, err := json.Marshal(receipt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rpt := std.Receipt{Name: name, ContractAddr: testAddress, Bytes: bz}
+	rpt.Hash = sha3.Sum256([]byte(rpt.Name), []byte(rpt.ContractAddr), bz)
+	value, err := json.Marshal(rpt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return cmn.KVPair{Key: []byte("/" + string(rune('0'+index)) + "/" + name), Value: value}
+}
+
+func TestReceipts(t *testing.T) {
+	assert := assert.New(t)
+
+	tags := []cmn.KVPair{
+		receiptTag(t, 0, receiptTransfer, std.Transfer{Token: "token", From: "alice", To: "bob", Value: bn.NString("18446744073709551616")}),
+		receiptTag(t, 1, receiptFee, std.Fee{Token: "token", From: "alice", Value: 1250000}),
+		receiptTag(t, 2, receiptBurn, std.Burn{Token: "token", Value: bn.N(10), TotalSupply: bn.N(90)}),
+		receiptTag(t, 3, receiptSetOwner, std.SetOwner{ContractAddr: "contract", NewOwner: "carol"}),
+		receiptTag(t, 4, "myContract.Event", map[string]string{"k": "v"}),
+	}
+
+	// receipt with wrong hash keeps raw data
+	bad := receiptTag(t, 5, receiptTransfer, std.Transfer{Token: "token", From: "alice", To: "eve", Value: bn.N(1)})
+	var rpt std.Receipt
+	assert.Nil(json.Unmarshal(bad.Value, &rpt))
+	rpt.Hash[0]++
+	bad.Value, _ = json.Marshal(rpt)
+	tags = append(tags, bad, cmn.KVPair{Key: []byte("other"), Value: []byte("not receipt")})
+
+	result := receipts(tags)
+	if !assert.Equal(6, len(result)) {
+		return
+	}
+
+	assert.Equal(Receipt{Name: receiptTransfer, ContractAddr: testAddress, Token: "token", From: "alice", To: "bob", Value: "18446744073709551616"}, result[0])
+	assert.Equal(Receipt{Name: receiptFee, ContractAddr: testAddress, Token: "token", From: "alice", Value: "1250000"}, result[1])
+	assert.Equal(Receipt{Name: receiptBurn, ContractAddr: testAddress, Token: "token", Value: "10", TotalSupply: "90"}, result[2])
+	assert.Equal(Receipt{Name: receiptSetOwner, ContractAddr: testAddress, SmcAddress: "contract", NewOwner: "carol"}, result[3])
+
+	assert.Equal("myContract.Event", result[4].Name)
+	assert.Equal(hex.EncodeToString([]byte(`{"k":"v"}`)), result[4].Bytes)
+	assert.NotEmpty(result[4].Hash)
+
+	assert.Equal("", result[5].To)
+	assert.NotEmpty(result[5].Bytes)
+}
@@ -0,0 +1,309 @@
+package rpc
+
+import (
+	"bcXwallet/common"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+)
+
+const defaultReconcileInterval = 1000 // blocks between snapshots if reconcileInterval is not set in config
+
+// tokenFlow - sums of transfers and fees of token in address history
+type tokenFlow struct {
+	in, out, fee *big.Int
+}
+
+func newTokenFlow() *tokenFlow {
+	return &tokenFlow{in: new(big.Int), out: new(big.Int), fee: new(big.Int)}
+}
+
+// StartReconciler - start balance reconciler of every chain if reconcileEnabled is true in config,
+// it needs address history
+func StartReconciler() {
+	if !common.GetConfig().ReconcileEnabled {
+		return
+	}
+
+	if !common.GetConfig().IndexerEnabled {
+		common.GetLogger().Error("Reconciler is not started, it needs address history indexer")
+		return
+	}
+
+	for _, c := range common.Chains() {
+		go followBlocks(c, "balance reconciler", reconcileOf(c))
+	}
+}
+
+// reconcileOf - scan function of followBlocks, take snapshots of chain
+func reconcileOf(c *common.Chain) func() (bool, error) {
+	return func() (bool, error) {
+		return reconcile(c)
+	}
+}
+
+func reconcileInterval() int64 {
+	if common.GetConfig().ReconcileInterval > 0 {
+		return common.GetConfig().ReconcileInterval
+	}
+
+	return defaultReconcileInterval
+}
+
+// nextReconcileHeight - snapshots are taken at multiples of interval, the first one is not lower than
+// indexerStartHeight-1 so that history after it is all indexed
+func nextReconcileHeight(last, indexerStartHeight, interval int64) int64 {
+	if last > 0 {
+		return last + interval
+	}
+
+	next := (indexerStartHeight - 1 + interval - 1) / interval * interval
+	if next < interval {
+		next = interval
+	}
+
+	return next
+}
+
+// reconcile - take snapshot of chain at the next height if it's indexed, it's caught up if the height is not indexed yet
+func reconcile(c *common.Chain) (caughtUp bool, err error) {
+
+	last, err := db.ReconciledHeight(c.Name)
+	if err != nil {
+		return
+	}
+
+	height := nextReconcileHeight(last, common.GetConfig().IndexerStartHeight, reconcileInterval())
+	indexed, err := indexedHeight(c)
+	if err != nil {
+		return
+	}
+	if height > indexed {
+		return true, nil
+	}
+
+	var previous *ReconcileReportResult
+	if last > 0 {
+		if previous, err = db.ReconcileReport(c.Name, last); err != nil {
+			return
+		}
+	}
+
+	report, err := reconcileAt(c, height, previous)
+	if err != nil {
+		return
+	}
+	if err = db.SaveReconcileReport(c.Name, report); err != nil {
+		return
+	}
+
+	if report.Discrepancies > 0 {
+		common.GetLogger().Warn("Balance discrepancies are found", "chain", c.Name, "height", height, "count", report.Discrepancies)
+		emitEvent(c, eventReconcileDiscrepancy, unbalancedWallets(report))
+	}
+
+	return false, nil
+}
+
+// reconcileAt - snapshot of all wallets at height compared with previous snapshot
+func reconcileAt(c *common.Chain, height int64, previous *ReconcileReportResult) (report *ReconcileReportResult, err error) {
+
+	wallets, err := walletAddresses(c)
+	if err != nil {
+		return
+	}
+	names := make([]string, 0, len(wallets))
+	for name := range wallets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	report = &ReconcileReportResult{Height: height, Time: time.Now().Unix(), Wallets: make([]ReconcileWallet, 0, len(names))}
+	previousWallets := make(map[string]*ReconcileWallet)
+	if previous != nil {
+		report.PreviousHeight = previous.Height
+		for i := range previous.Wallets {
+			previousWallets[previous.Wallets[i].Address] = &previous.Wallets[i]
+		}
+	}
+
+	for _, name := range names {
+		address := wallets[name]
+
+		var balances *[]AllBalanceItemResult
+		if balances, err = allBalance(c, address, height); err != nil {
+			return nil, fmt.Errorf("Cannot get balance of %s at height %d, %s", address, height, err.Error())
+		}
+
+		wallet := ReconcileWallet{Name: name, Address: address, Balanced: true, Tokens: make([]ReconcileToken, 0)}
+		for _, item := range *balances {
+			wallet.Tokens = append(wallet.Tokens, ReconcileToken{Token: item.TokenAddress, TokenName: item.TokenName, Balance: item.Balance})
+		}
+
+		prevWallet, ok := previousWallets[address]
+		if !ok {
+			wallet.Baseline = true
+			report.Wallets = append(report.Wallets, wallet)
+			continue
+		}
+
+		var items []HistoryItem
+		if items, err = historyBetween(c, address, report.PreviousHeight, height); err != nil {
+			return
+		}
+		if err = compareSnapshot(&wallet, prevWallet, items); err != nil {
+			return
+		}
+
+		for _, token := range wallet.Tokens {
+			if token.Discrepancy != "0" {
+				report.Discrepancies++
+			}
+		}
+		report.Wallets = append(report.Wallets, wallet)
+	}
+
+	return
+}
+
+// historyBetween - all history items of address on chain in (fromHeight, toHeight]
+func historyBetween(c *common.Chain, address string, fromHeight, toHeight int64) ([]HistoryItem, error) {
+	items := make([]HistoryItem, 0)
+
+	for cursor := ""; ; {
+		page, next, err := db.History(c.Name, address, "", fromHeight+1, toHeight, cursor, maxHistoryLimit)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, page...)
+		if next == "" {
+			return items, nil
+		}
+		cursor = next
+	}
+}
+
+// compareSnapshot - fill previous balance, change, flows and discrepancy of every token in wallet or previous wallet
+func compareSnapshot(wallet, previous *ReconcileWallet, items []HistoryItem) error {
+
+	previousBalances := make(map[string]string)
+	for _, token := range previous.Tokens {
+		previousBalances[token.Token] = token.Balance
+	}
+
+	// tokens that are gone since previous snapshot, or only seen in history, have zero balance now
+	seen := make(map[string]bool)
+	for _, token := range wallet.Tokens {
+		seen[token.Token] = true
+	}
+	for _, token := range previous.Tokens {
+		if !seen[token.Token] {
+			seen[token.Token] = true
+			wallet.Tokens = append(wallet.Tokens, ReconcileToken{Token: token.Token, TokenName: token.TokenName, Balance: "0"})
+		}
+	}
+	for _, item := range items {
+		if !seen[item.Token] {
+			seen[item.Token] = true
+			wallet.Tokens = append(wallet.Tokens, ReconcileToken{Token: item.Token, Balance: "0"})
+		}
+	}
+
+	flows := make(map[string]*tokenFlow)
+	for _, item := range items {
+		value, ok := new(big.Int).SetString(item.Value, 10)
+		if !ok {
+			return errors.New("Invalid value " + item.Value + " of transaction " + item.TxHash)
+		}
+
+		flow, ok := flows[item.Token]
+		if !ok {
+			flow = newTokenFlow()
+			flows[item.Token] = flow
+		}
+		switch item.Direction {
+		case directionIn:
+			flow.in.Add(flow.in, value)
+		case directionOut:
+			flow.out.Add(flow.out, value)
+		case directionFee:
+			flow.fee.Add(flow.fee, value)
+		}
+	}
+
+	for i := range wallet.Tokens {
+		token := &wallet.Tokens[i]
+
+		previousBalance, current := new(big.Int), new(big.Int)
+		if balance, ok := previousBalances[token.Token]; ok {
+			if _, ok = previousBalance.SetString(balance, 10); !ok {
+				return errors.New("Invalid balance " + balance + " in previous snapshot")
+			}
+		}
+		if _, ok := current.SetString(token.Balance, 10); !ok {
+			return errors.New("Invalid balance " + token.Balance)
+		}
+
+		flow, ok := flows[token.Token]
+		if !ok {
+			flow = newTokenFlow()
+		}
+		change := new(big.Int).Sub(current, previousBalance)
+		expected := new(big.Int).Sub(flow.in, flow.out)
+		expected.Sub(expected, flow.fee)
+		discrepancy := new(big.Int).Sub(change, expected)
+
+		token.PreviousBalance = previousBalance.String()
+		token.Change = change.String()
+		token.In = flow.in.String()
+		token.Out = flow.out.String()
+		token.Fee = flow.fee.String()
+		token.Discrepancy = discrepancy.String()
+		if discrepancy.Sign() != 0 {
+			wallet.Balanced = false
+		}
+	}
+
+	return nil
+}
+
+// unbalancedWallets - report with only the wallets that have discrepancy
+func unbalancedWallets(report *ReconcileReportResult) *ReconcileReportResult {
+	unbalanced := *report
+	unbalanced.Wallets = make([]ReconcileWallet, 0)
+	for _, wallet := range report.Wallets {
+		if !wallet.Balanced {
+			unbalanced.Wallets = append(unbalanced.Wallets, wallet)
+		}
+	}
+
+	return &unbalanced
+}
+
+// reconcileReport - report of snapshot of chain at height, 0 means the latest one
+func reconcileReport(c *common.Chain, height int64) (report *ReconcileReportResult, err error) {
+
+	if !common.GetConfig().ReconcileEnabled {
+		return nil, errors.New("Balance reconciliation is not enabled ")
+	}
+
+	if height == 0 {
+		if height, err = db.ReconciledHeight(c.Name); err != nil {
+			return
+		}
+		if height == 0 {
+			return nil, errors.New("No snapshot is taken yet ")
+		}
+	}
+
+	if report, err = db.ReconcileReport(c.Name, height); err != nil {
+		return
+	}
+	if report == nil {
+		return nil, fmt.Errorf("No snapshot at height %d, snapshots are taken every %d blocks ", height, reconcileInterval())
+	}
+
+	return
+}
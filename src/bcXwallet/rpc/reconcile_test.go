@@ -0,0 +1,116 @@
+package rpc
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"bcXwallet/common"
+	"bcXwallet/common/config"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNextReconcileHeight(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(int64(100), nextReconcileHeight(0, 1, 100))
+	assert.Equal(int64(100), nextReconcileHeight(0, 101, 100))
+	assert.Equal(int64(200), nextReconcileHeight(0, 102, 100))
+	assert.Equal(int64(300), nextReconcileHeight(200, 102, 100))
+}
+
+func TestCompareSnapshot(t *testing.T) {
+	assert := assert.New(t)
+	defer openTestDB(t)()
+
+	entries := []historyEntry{
+		{Address: testAddress, Suffix: "00000000000000000101/000000/0000",
+			Item: HistoryItem{TxHash: "0x01", Height: 101, Direction: directionIn, Token: "bcbToken", Value: "1000", From: "bcbOther"}},
+		{Address: testAddress, Suffix: "00000000000000000150/000000/0000",
+			Item: HistoryItem{TxHash: "0x02", Height: 150, Direction: directionOut, Token: "bcbToken", Value: "300", To: "bcbOther"}},
+		{Address: testAddress, Suffix: "00000000000000000150/000000/0001",
+			Item: HistoryItem{TxHash: "0x02", Height: 150, Direction: directionFee, Token: "bcbToken", Value: "50"}},
+		{Address: testAddress, Suffix: "00000000000000000250/000000/0000",
+			Item: HistoryItem{TxHash: "0x03", Height: 250, Direction: directionIn, Token: "bcbToken", Value: "1"}},
+	}
+	assert.Nil(db.SaveHistory(testChain.Name, 300, entries))
+
+	// only the history after the previous snapshot counts
+	items, err := historyBetween(testChain, testAddress, 100, 200)
+	assert.Nil(err)
+	assert.Equal(3, len(items))
+
+	previous := &ReconcileWallet{Address: testAddress, Tokens: []ReconcileToken{
+		{Token: "bcbToken", Balance: "500"},
+		{Token: "bcbGone", TokenName: "gone", Balance: "7"},
+	}}
+	wallet := &ReconcileWallet{Address: testAddress, Balanced: true, Tokens: []ReconcileToken{{Token: "bcbToken", Balance: "1150"}}}
+	assert.Nil(compareSnapshot(wallet, previous, items))
+	if assert.Equal(2, len(wallet.Tokens)) {
+		token := wallet.Tokens[0]
+		assert.Equal("500", token.PreviousBalance)
+		assert.Equal("650", token.Change)
+		assert.Equal("1000", token.In)
+		assert.Equal("300", token.Out)
+		assert.Equal("50", token.Fee)
+		assert.Equal("0", token.Discrepancy)
+
+		// balance that is gone without transfer is a discrepancy
+		gone := wallet.Tokens[1]
+		assert.Equal("bcbGone", gone.Token)
+		assert.Equal("0", gone.Balance)
+		assert.Equal("-7", gone.Discrepancy)
+	}
+	assert.False(wallet.Balanced)
+
+	report := &ReconcileReportResult{Height: 200, PreviousHeight: 100, Discrepancies: 1, Wallets: []ReconcileWallet{*wallet}}
+	assert.Nil(db.SaveReconcileReport(testChain.Name, report))
+	height, err := db.ReconciledHeight(testChain.Name)
+	assert.Nil(err)
+	assert.Equal(int64(200), height)
+	saved, err := db.ReconcileReport(testChain.Name, 200)
+	assert.Nil(err)
+	assert.Equal(report, saved)
+	saved, err = db.ReconcileReport(testChain.Name, 100)
+	assert.Nil(err)
+	assert.Nil(saved)
+}
+
+func TestReconcileUnfundedWallet(t *testing.T) {
+	assert := assert.New(t)
+	defer openTestDB(t)()
+
+	// the node has no state of the account, it's never funded
+	node := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Params struct {
+				Height json.Number `json:"height"`
+			} `json:"params"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		w.Write([]byte(`{"jsonrpc":"2.0","id":"jsonrpc-client","result":{"response":{"code":200,"height":` + req.Params.Height.String() + `}}}`))
+	}))
+	defer node.Close()
+	c := common.NewChain(config.ChainProfile{Name: "reconcile", ChainID: testChainID, ChainVersion: "2", NodeAddrSlice: []string{node.URL}})
+	acct, accessKey, err := newAccount(c, "alice", "Ab1@Cd2$")
+	assert.Nil(err)
+	assert.Nil(acct.Save(c.KeyStoreNamespace, accessKey))
+
+	report, err := reconcileAt(c, 100, nil)
+	assert.Nil(err)
+	if assert.Equal(1, len(report.Wallets)) {
+		assert.Equal(acct.Address, report.Wallets[0].Address)
+		assert.Equal(0, len(report.Wallets[0].Tokens))
+		assert.True(report.Wallets[0].Baseline)
+	}
+
+	report, err = reconcileAt(c, 200, report)
+	assert.Nil(err)
+	if assert.Equal(1, len(report.Wallets)) {
+		assert.False(report.Wallets[0].Baseline)
+		assert.True(report.Wallets[0].Balanced)
+	}
+	assert.Equal(0, report.Discrepancies)
+}
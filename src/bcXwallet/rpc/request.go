@@ -0,0 +1,217 @@
+package rpc
+
+import (
+	"bcXwallet/common"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"sync"
+
+	types2 "github.com/tendermint/abci/types"
+	"github.com/tendermint/tendermint/rpc/core/types"
+	tmtypes "github.com/tendermint/tendermint/types"
+)
+
+// status of request record
+const (
+	requestStatusPending   = "pending"   // tx is signed, result of broadcast is unknown
+	requestStatusCommitted = "committed" // result of broadcast is saved
+)
+
+// requestRecord - the tx and result of bcb_transfer or bcb_commitTx with client's requestId
+type requestRecord struct {
+	Method     string         `json:"method"`
+	ParamsHash string         `json:"paramsHash"`
+	Status     string         `json:"status"`
+	Tx         string         `json:"tx"`
+	TxHash     string         `json:"txHash"`
+	Result     CommitTxResult `json:"result"`
+}
+
+var (
+	requestsInFlight    = make(map[string]struct{})
+	requestsInFlightMtx sync.Mutex
+)
+
+// hashOfParams - hash of request method and parameters, used to detect requestId reuse
+func hashOfParams(method string, params ...string) string {
+	hasher := sha256.New()
+	hasher.Write([]byte(method))
+	for _, param := range params {
+		hasher.Write([]byte{0})
+		hasher.Write([]byte(param))
+	}
+
+	return hex.EncodeToString(hasher.Sum(nil))
+}
+
+// checkRequestID - requestId is optional, a given one must not be blank
+func checkRequestID(requestID string) error {
+	if len(strings.TrimSpace(requestID)) < 1 || len(requestID) > 128 {
+		return errors.New("RequestId length must be [1-128] ")
+	}
+
+	return nil
+}
+
+// lockRequest - only one call with the same requestId can run at one time
+func lockRequest(requestID string) (unlock func(), err error) {
+	requestsInFlightMtx.Lock()
+	defer requestsInFlightMtx.Unlock()
+
+	if _, ok := requestsInFlight[requestID]; ok {
+		return nil, errors.New("The request of requestId=" + requestID + " is in progress, please retry later ")
+	}
+	requestsInFlight[requestID] = struct{}{}
+
+	return func() {
+		requestsInFlightMtx.Lock()
+		delete(requestsInFlight, requestID)
+		requestsInFlightMtx.Unlock()
+	}, nil
+}
+
+// commitWithRequestID - commit the tx generated by genTx only once for requestId,
+// a repeat with the same parameters returns the original result, broadcast tells whether this call broadcast the tx
+func commitWithRequestID(c *common.Chain, requestID, method, paramsHash string, genTx func() (string, error)) (commit *CommitTxResult, broadcast bool, err error) {
+
+	unlock, err := lockRequest(requestID)
+	if err != nil {
+		return
+	}
+	defer unlock()
+
+	record, err := db.RequestRecord(requestID)
+	if err != nil {
+		return
+	}
+
+	if record != nil {
+		if record.Method != method || record.ParamsHash != paramsHash {
+			return nil, false, errors.New("Conflict: requestId=" + requestID + " was already used by " + record.Method + " with different parameters ")
+		}
+
+		return resumeRequest(c, requestID, record)
+	}
+
+	var txStr string
+	if txStr, err = genTx(); err != nil {
+		return
+	}
+
+	record = &requestRecord{
+		Method:     method,
+		ParamsHash: paramsHash,
+		Status:     requestStatusPending,
+		Tx:         txStr,
+		TxHash:     "0x" + hex.EncodeToString(tmtypes.Tx(txStr).Hash()),
+	}
+	if err = db.SetRequestRecord(requestID, record); err != nil {
+		return
+	}
+
+	return broadcastRequest(c, requestID, record)
+}
+
+// resumeRequest - return the saved result, or find out the result of pending tx,
+// the tx is broadcast again only if a node answered that it's not found and it's not in mempool either
+func resumeRequest(c *common.Chain, requestID string, record *requestRecord) (commit *CommitTxResult, broadcast bool, err error) {
+
+	if record.Status == requestStatusCommitted {
+		result := record.Result
+		return &result, false, nil
+	}
+
+	// the tx may be broadcast before, query it first
+	result := new(core_types.ResultTx)
+	params := map[string]interface{}{"hash": strings.TrimPrefix(record.TxHash, "0x")}
+	err = common.DoHttpRequestAndParseEx(c.NodeAddrSlice, "tx", params, result)
+	if _, notFound := err.(*common.ResponseError); notFound {
+		// the tx route only finds checked or committed txs, the tx may still wait in mempool
+		var inMempool bool
+		if inMempool, err = txInMempool(c, record.TxHash); err != nil {
+			return
+		}
+		if !inMempool {
+			return resendRequest(c, requestID, record)
+		}
+	} else if err != nil {
+		// the tx may be committed, it's not broadcast again until a node answers
+		return nil, false, errors.New("Cannot get the transaction of requestId=" + requestID + ", please retry later: " + err.Error())
+	}
+
+	if result.Height == 0 {
+		return nil, false, errors.New("The transaction of requestId=" + requestID + " is pending, txHash=" + record.TxHash)
+	}
+
+	record.Status = requestStatusCommitted
+	record.Result = CommitTxResult{
+		Code:   result.DeliverResult.Code,
+		Log:    result.DeliverResult.Log,
+		Fee:    result.DeliverResult.Fee,
+		TxHash: record.TxHash,
+		Height: result.Height,
+	}
+	if err = db.SetRequestRecord(requestID, record); err != nil {
+		return
+	}
+
+	commit = new(CommitTxResult)
+	*commit = record.Result
+
+	return
+}
+
+// txInMempool - whether tx of hash is in mempool of node
+func txInMempool(c *common.Chain, txHash string) (bool, error) {
+
+	unconfirmed := new(core_types.ResultUnconfirmedTxs)
+	err := common.DoHttpRequestAndParseEx(c.NodeAddrSlice, "unconfirmed_txs", map[string]interface{}{}, unconfirmed)
+	if err != nil {
+		return false, err
+	}
+
+	for _, tx := range unconfirmed.Txs {
+		if "0x"+hex.EncodeToString(tx.Hash()) == txHash {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func broadcastRequest(c *common.Chain, requestID string, record *requestRecord) (commit *CommitTxResult, broadcast bool, err error) {
+
+	broadcast = true
+	if commit, err = commitTx(c, record.Tx); err != nil {
+		return
+	}
+
+	record.Status = requestStatusCommitted
+	record.Result = *commit
+	err = db.SetRequestRecord(requestID, record)
+
+	return
+}
+
+// resendRequest - broadcast the pending tx again, the first broadcast may be committed by now,
+// so rejection of CheckTx is not the result of the tx and the record is kept pending
+func resendRequest(c *common.Chain, requestID string, record *requestRecord) (commit *CommitTxResult, broadcast bool, err error) {
+
+	result, err := common.DoHttpRequestAndParse(c.NodeAddrSlice, record.Tx)
+	if err != nil {
+		return
+	}
+	if result.CheckTx.Code != types2.CodeTypeOK {
+		return nil, false, errors.New("The transaction of requestId=" + requestID + " is pending, it's rejected when broadcast again: " +
+			result.CheckTx.Log + ", txHash=" + record.TxHash)
+	}
+
+	commit = commitResultOf(result)
+	record.Status = requestStatusCommitted
+	record.Result = *commit
+	err = db.SetRequestRecord(requestID, record)
+
+	return commit, true, err
+}
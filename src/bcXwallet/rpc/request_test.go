@@ -0,0 +1,165 @@
+package rpc
+
+import (
+	"bcXwallet/common"
+	"bcXwallet/common/config"
+	"common/bcdb"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	tmtypes "github.com/tendermint/tendermint/types"
+)
+
+func openTestDB(t *testing.T) func() {
+	dir, err := ioutil.TempDir("", "bcbXwallet")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db.GILevelDB, err = bcdb.OpenDB(filepath.Join(dir, dbName), "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return func() {
+		db.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestCommitWithRequestID(t *testing.T) {
+	assert := assert.New(t)
+	defer openTestDB(t)()
+
+	paramsHash := hashOfParams("bcb_transfer", "alice", "1000")
+	record := &requestRecord{
+		Method:     "bcb_transfer",
+		ParamsHash: paramsHash,
+		Status:     requestStatusCommitted,
+		Tx:         "bcb<tx>.v2.payload.<1>.sig",
+		TxHash:     "0x01",
+		Result:     CommitTxResult{Code: 200, Fee: 1250000, TxHash: "0x01", Height: 10},
+	}
+	assert.Nil(db.SetRequestRecord("req-1", record))
+
+	genTx := func() (string, error) {
+		t.Fatal("tx must not be signed again")
+		return "", nil
+	}
+
+	// repeat with the same parameters returns the original result
+	commit, broadcast, err := commitWithRequestID(testChain, "req-1", "bcb_transfer", paramsHash, genTx)
+	if assert.Nil(err) {
+		assert.Equal(record.Result, *commit)
+		assert.False(broadcast)
+	}
+
+	// repeat with different parameters is a conflict
+	_, _, err = commitWithRequestID(testChain, "req-1", "bcb_transfer", hashOfParams("bcb_transfer", "alice", "1001"), genTx)
+	assert.NotNil(err)
+
+	_, _, err = commitWithRequestID(testChain, "req-1", "bcb_commitTx", paramsHash, genTx)
+	assert.NotNil(err)
+}
+
+func TestLockRequest(t *testing.T) {
+	assert := assert.New(t)
+
+	unlock, err := lockRequest("req-2")
+	assert.Nil(err)
+
+	_, err = lockRequest("req-2")
+	assert.NotNil(err)
+
+	unlock()
+	unlock, err = lockRequest("req-2")
+	assert.Nil(err)
+	unlock()
+}
+
+func TestResumeRequest(t *testing.T) {
+	assert := assert.New(t)
+	defer openTestDB(t)()
+
+	tx := "bcb<tx>.v2.payload.<2>.sig"
+	txHash := "0x" + hex.EncodeToString(tmtypes.Tx(tx).Hash())
+	newRecord := func(requestID string) *requestRecord {
+		record := &requestRecord{Method: "bcb_commitTx", Status: requestStatusPending, Tx: tx, TxHash: txHash}
+		assert.Nil(db.SetRequestRecord(requestID, record))
+		return record
+	}
+
+	// the node has not found the tx, the answers of mempool and broadcast are set by each case
+	var mempool, broadcastResult string
+	var broadcasts int
+	node := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string `json:"method"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		switch req.Method {
+		case "tx":
+			w.Write([]byte(`{"jsonrpc":"2.0","id":"jsonrpc-client","error":{"code":-32603,"message":"Internal error","data":"tx not found"}}`))
+		case "unconfirmed_txs":
+			w.Write([]byte(`{"jsonrpc":"2.0","id":"jsonrpc-client","result":{"n_txs":1,"txs":["` + mempool + `"]}}`))
+		case "broadcast_tx_commit":
+			broadcasts++
+			w.Write([]byte(`{"jsonrpc":"2.0","id":"jsonrpc-client","result":` + broadcastResult + `}`))
+		}
+	}))
+	defer node.Close()
+	c := common.NewChain(config.ChainProfile{Name: "resume", ChainID: testChainID, NodeAddrSlice: []string{node.URL}})
+
+	// the tx waits in mempool, it's not broadcast again
+	mempool = base64.StdEncoding.EncodeToString([]byte(tx))
+	_, _, err := resumeRequest(c, "req-3", newRecord("req-3"))
+	assert.Contains(err.Error(), "is pending")
+	assert.Equal(0, broadcasts)
+
+	// the resent tx is rejected by CheckTx, it's not the result of the first broadcast
+	mempool = base64.StdEncoding.EncodeToString([]byte("other"))
+	broadcastResult = `{"check_tx":{"code":2007,"log":"invalid nonce"}}`
+	_, broadcast, err := resumeRequest(c, "req-4", newRecord("req-4"))
+	assert.Contains(err.Error(), "invalid nonce")
+	assert.False(broadcast)
+	assert.Equal(1, broadcasts)
+	record, err := db.RequestRecord("req-4")
+	assert.Nil(err)
+	assert.Equal(requestStatusPending, record.Status)
+
+	broadcastResult = `{"check_tx":{"code":200},"deliver_tx":{"code":200},"height":12}`
+	commit, broadcast, err := resumeRequest(c, "req-4", record)
+	if assert.Nil(err) {
+		assert.True(broadcast)
+		assert.Equal(int64(12), commit.Height)
+	}
+	record, err = db.RequestRecord("req-4")
+	assert.Nil(err)
+	assert.Equal(requestStatusCommitted, record.Status)
+
+	// the node is unreachable, the tx may be committed so it's not broadcast again
+	node.Close()
+	_, _, err = resumeRequest(c, "req-5", newRecord("req-5"))
+	assert.NotNil(err)
+	assert.Equal(2, broadcasts)
+	record, err = db.RequestRecord("req-5")
+	assert.Nil(err)
+	assert.Equal(requestStatusPending, record.Status)
+}
+
+func TestCheckRequestID(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Nil(checkRequestID("req-1"))
+	assert.NotNil(checkRequestID(""))
+	assert.NotNil(checkRequestID("  "))
+	assert.NotNil(checkRequestID(string(make([]byte, 129))))
+}
@@ -6,21 +6,54 @@ import (
 
 var Routes = map[string]*rpcserver.RPCFunc{
 	// bcbXWallet api
-	"bcb_walletCreate":    rpcserver.NewRPCFunc(WalletCreate, "name,password"),
-	"bcb_walletExport":    rpcserver.NewRPCFunc(WalletExport, "name,password,accessKey,plainText"),
-	"bcb_walletImport":    rpcserver.NewRPCFunc(WalletImport, "name,privateKey,password,accessKey,plainText"),
-	"bcb_walletList":      rpcserver.NewRPCFunc(WalletList, "pageNum"),
-	"bcb_transfer":        rpcserver.NewRPCFunc(WalletTransfer, "name,accessKey,walletParams"),
-	"bcb_transferOffline": rpcserver.NewRPCFunc(WalletTransferOffline, "name,accessKey,walletParams"),
+	"bcb_walletCreate":     rpcserver.NewRPCFunc(WalletCreate, "name,password,chain"),
+	"bcb_walletExport":     rpcserver.NewRPCFunc(WalletExport, "name,password,accessKey,plainText,chain"),
+	"bcb_walletImport":     rpcserver.NewRPCFunc(WalletImport, "name,privateKey,password,accessKey,plainText,chain"),
+	"bcb_walletList":       rpcserver.NewRPCFunc(WalletList, "pageNum,chain"),
+	"bcb_transfer":         rpcserver.NewRPCFunc(WalletTransfer, "name,accessKey,walletParams,requestId,chain"),
+	"bcb_transferOffline":  rpcserver.NewRPCFunc(WalletTransferOffline, "name,accessKey,walletParams,chain"),
+	"bcb_multisigCreate":   rpcserver.NewRPCFunc(MultisigCreate, "walletParams,chain"),
+	"bcb_multisigSign":     rpcserver.NewRPCFunc(MultisigSign, "name,accessKey,id,chain"),
+	"bcb_multisigSigners":  rpcserver.NewRPCFunc(MultisigSigners, "id,chain"),
+	"bcb_multisigExport":   rpcserver.NewRPCFunc(MultisigExport, "id,chain"),
+	"bcb_multisigImport":   rpcserver.NewRPCFunc(MultisigImport, "data,chain"),
+	"bcb_multisigFinalize": rpcserver.NewRPCFunc(MultisigFinalize, "id,chain"),
 
 	// block chain api
-	"bcb_blockHeight":    rpcserver.NewRPCFunc(BlockHeight, ""),
-	"bcb_block":          rpcserver.NewRPCFunc(Block, "height"),
-	"bcb_transaction":    rpcserver.NewRPCFunc(Transaction, "txHash"),
-	"bcb_balance":        rpcserver.NewRPCFunc(Balance, "address"),
-	"bcb_balanceOfToken": rpcserver.NewRPCFunc(BalanceOfToken, "address,tokenAddress,tokenName"),
-	"bcb_allBalance":     rpcserver.NewRPCFunc(AllBalance, "address"),
-	"bcb_nonce":          rpcserver.NewRPCFunc(Nonce, "address"),
-	"bcb_commitTx":       rpcserver.NewRPCFunc(CommitTx, "tx"),
-	"bcb_version":        rpcserver.NewRPCFunc(Version, ""),
+	"bcb_status":           rpcserver.NewRPCFunc(Status, "chain"),
+	"bcb_chainVersion":     rpcserver.NewRPCFunc(ChainVersion, "chain"),
+	"bcb_validators":       rpcserver.NewRPCFunc(Validators, "height,chain"),
+	"bcb_netInfo":          rpcserver.NewRPCFunc(NetInfo, "chain"),
+	"bcb_blockHeight":      rpcserver.NewRPCFunc(BlockHeight, "chain"),
+	"bcb_block":            rpcserver.NewRPCFunc(Block, "height,amountUnit,chain"),
+	"bcb_blocks":           rpcserver.NewRPCFunc(Blocks, "minHeight,maxHeight,detail,amountUnit,chain"),
+	"bcb_transaction":      rpcserver.NewRPCFunc(Transaction, "txHash,amountUnit,chain"),
+	"bcb_pendingTxs":       rpcserver.NewRPCFunc(PendingTxs, "address,chain"),
+	"bcb_balance":          rpcserver.NewRPCFunc(Balance, "address,amountUnit,height,chain"),
+	"bcb_balanceOfToken":   rpcserver.NewRPCFunc(BalanceOfToken, "address,tokenAddress,tokenName,amountUnit,height,chain"),
+	"bcb_allBalance":       rpcserver.NewRPCFunc(AllBalance, "address,amountUnit,height,chain"),
+	"bcb_balanceAtTime":    rpcserver.NewRPCFunc(BalanceAtTime, "address,token,timestamp,amountUnit,chain"),
+	"bcb_nonce":            rpcserver.NewRPCFunc(Nonce, "address,height,chain"),
+	"bcb_tokens":           rpcserver.NewRPCFunc(Tokens, "chain"),
+	"bcb_contract":         rpcserver.NewRPCFunc(Contract, "address,height,chain"),
+	"bcb_contractVersions": rpcserver.NewRPCFunc(ContractVersions, "orgID,name,height,chain"),
+	"bcb_organization":     rpcserver.NewRPCFunc(Organization, "orgID,chain"),
+	"bcb_tokenInfo":        rpcserver.NewRPCFunc(TokenInfo, "tokenAddress,tokenName,tokenSymbol,chain"),
+	"bcb_addressHistory":   rpcserver.NewRPCFunc(AddressHistory, "address,token,fromHeight,toHeight,cursor,limit,chain"),
+	"bcb_statement":        rpcserver.NewRPCFunc(Statement, "address,token,fromTime,toTime,format,chain"),
+	"bcb_reconcileReport":  rpcserver.NewRPCFunc(ReconcileReport, "height,chain"),
+	"bcb_deposits":         rpcserver.NewRPCFunc(Deposits, "sinceId,limit,chain"),
+	"bcb_depositAck":       rpcserver.NewRPCFunc(DepositAck, "id,chain"),
+	"bcb_webhookReplay":    rpcserver.NewRPCFunc(WebhookReplay, "id,chain"),
+	"bcb_commitTx":         rpcserver.NewRPCFunc(CommitTx, "tx,requestId,chain"),
+	"bcb_signedQuery":      rpcserver.NewRPCFunc(SignedQuery, "name,accessKey,key,chain"),
+	"bcb_convertUnit":      rpcserver.NewRPCFunc(ConvertUnit, "value,amountUnit,chain"),
+	"bcb_nodes":            rpcserver.NewRPCFunc(Nodes, "chain"),
+	"bcb_metaCacheStats":   rpcserver.NewRPCFunc(MetaCacheStats, "chain"),
+	"bcb_version":          rpcserver.NewRPCFunc(Version, "chain"),
+
+	// websocket api
+	"subscribe":       rpcserver.NewWSRPCFunc(Subscribe, "topic,chain"),
+	"unsubscribe":     rpcserver.NewWSRPCFunc(Unsubscribe, "topic,chain"),
+	"unsubscribe_all": rpcserver.NewWSRPCFunc(UnsubscribeAll, "chain"),
 }
@@ -0,0 +1,16 @@
+package rpc
+
+// StartServices - init query signer and start background jobs enabled in config, every binary that
+// serves Routes calls it after InitDB
+func StartServices() error {
+	if err := InitQuerySigner(); err != nil {
+		return err
+	}
+
+	StartNotifier()
+	StartIndexer()
+	StartDepositWatcher()
+	StartReconciler()
+
+	return nil
+}
@@ -21,6 +21,14 @@ func keyOfTokenName(tokenName string) string {
 	return "/token/name/" + strings.ToLower(tokenName)
 }
 
+func keyOfTokenSymbol(tokenSymbol string) string {
+	return "/token/symbol/" + strings.ToLower(tokenSymbol)
+}
+
+func keyOfAllToken() string {
+	return "/token/all/0"
+}
+
 func keyOfAccountToken(exAddress smc.Address, contractAddr smc.Address) string {
 	return "/account/ex/" + exAddress + "/token/" + contractAddr
 }
@@ -0,0 +1,234 @@
+package rpc
+
+import (
+	"bcXwallet/common"
+	"blockchain/abciapp_v1.0/keys"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"time"
+)
+
+// formats of statement content
+const (
+	statementFormatCSV   = "csv"
+	statementFormatJSONL = "jsonl"
+)
+
+// types of statement line besides the directions of history items
+const (
+	statementOpening = "opening"
+	statementClosing = "closing"
+)
+
+var statementCSVHeader = []string{"type", "time", "txHash", "height", "counterparty", "token", "in", "out", "fee", "balance"}
+
+func checkStatementFormat(format string) error {
+	if format != "" && format != statementFormatCSV && format != statementFormatJSONL {
+		return errors.New("Format must be csv or jsonl ")
+	}
+
+	return nil
+}
+
+// statement - transfers and fees of address in token between fromTime and toTime from address history,
+// the running balance starts with the on-chain balance of the last block before fromTime
+func statement(c *common.Chain, address, token keys.Address, fromTime, toTime int64, format string) (result *StatementResult, err error) {
+
+	if !common.GetConfig().IndexerEnabled {
+		return nil, errors.New("Address history indexer is not enabled ")
+	}
+
+	if token == "" {
+		token = genesisToken(c)
+	}
+	if format == "" {
+		format = statementFormatCSV
+	}
+
+	// opening height is 0 if fromTime is not later than the first block
+	openHeight := int64(0)
+	firstTime, err := blockTime(c, 1)
+	if err != nil {
+		return
+	}
+	if firstTime.Unix() < fromTime {
+		if openHeight, _, err = heightAtTime(c, fromTime-1); err != nil {
+			return
+		}
+	}
+	closeHeight, _, err := heightAtTime(c, toTime)
+	if err != nil {
+		return
+	}
+
+	if common.GetConfig().IndexerStartHeight > openHeight+1 {
+		return nil, fmt.Errorf("Address history is indexed from height %d, statement needs height %d ",
+			common.GetConfig().IndexerStartHeight, openHeight+1)
+	}
+	indexed, err := indexedHeight(c)
+	if err != nil {
+		return
+	}
+	if indexed < closeHeight {
+		return nil, fmt.Errorf("Address history is indexed up to height %d, statement needs height %d ", indexed, closeHeight)
+	}
+
+	opening := "0"
+	if openHeight > 0 {
+		var balanceResult *BalanceResult
+		if balanceResult, err = balanceOfToken(c, address, token, "", openHeight); err != nil {
+			return
+		}
+		opening = balanceResult.Balance
+	}
+	closingResult, err := balanceOfToken(c, address, token, "", closeHeight)
+	if err != nil {
+		return
+	}
+
+	items := make([]HistoryItem, 0)
+	for cursor := ""; closeHeight > openHeight; {
+		var page []HistoryItem
+		if page, cursor, err = db.History(c.Name, address, token, openHeight+1, closeHeight, cursor, maxHistoryLimit); err != nil {
+			return
+		}
+		items = append(items, page...)
+		if cursor == "" {
+			break
+		}
+	}
+
+	result = &StatementResult{
+		Address:        address,
+		Token:          token,
+		FromHeight:     openHeight,
+		ToHeight:       closeHeight,
+		OpeningBalance: opening,
+		ClosingBalance: closingResult.Balance,
+		Format:         format,
+	}
+
+	lines, err := statementLines(result, fromTime, toTime, items, func(height int64) (time.Time, error) {
+		return blockTime(c, height)
+	})
+	if err != nil {
+		return
+	}
+	result.Content, err = renderStatement(lines, format)
+
+	return
+}
+
+// statementLines - opening line, a line of every history item with running balance and closing line,
+// totals of result are filled
+func statementLines(result *StatementResult, fromTime, toTime int64, items []HistoryItem,
+	timeOf func(height int64) (time.Time, error)) (lines []StatementLine, err error) {
+
+	balance, ok := new(big.Int).SetString(result.OpeningBalance, 10)
+	if !ok {
+		return nil, errors.New("Invalid opening balance " + result.OpeningBalance)
+	}
+	totalIn, totalOut, totalFee := new(big.Int), new(big.Int), new(big.Int)
+
+	lines = make([]StatementLine, 0, len(items)+2)
+	lines = append(lines, StatementLine{
+		Type:    statementOpening,
+		Time:    time.Unix(fromTime, 0).UTC().Format(time.RFC3339),
+		Height:  result.FromHeight,
+		Token:   result.Token,
+		Balance: balance.String(),
+	})
+
+	times := make(map[int64]string)
+	for _, item := range items {
+		value, ok := new(big.Int).SetString(item.Value, 10)
+		if !ok {
+			return nil, errors.New("Invalid value " + item.Value + " of transaction " + item.TxHash)
+		}
+
+		if _, ok := times[item.Height]; !ok {
+			var blkTime time.Time
+			if blkTime, err = timeOf(item.Height); err != nil {
+				return
+			}
+			times[item.Height] = blkTime.UTC().Format(time.RFC3339)
+		}
+
+		line := StatementLine{Type: item.Direction, Time: times[item.Height], TxHash: item.TxHash, Height: item.Height, Token: item.Token}
+		switch item.Direction {
+		case directionIn:
+			line.Counterparty = item.From
+			line.In = value.String()
+			totalIn.Add(totalIn, value)
+			balance.Add(balance, value)
+		case directionOut:
+			line.Counterparty = item.To
+			line.Out = value.String()
+			totalOut.Add(totalOut, value)
+			balance.Sub(balance, value)
+		case directionFee:
+			line.Fee = value.String()
+			totalFee.Add(totalFee, value)
+			balance.Sub(balance, value)
+		}
+		line.Balance = balance.String()
+
+		lines = append(lines, line)
+	}
+
+	lines = append(lines, StatementLine{
+		Type:    statementClosing,
+		Time:    time.Unix(toTime, 0).UTC().Format(time.RFC3339),
+		Height:  result.ToHeight,
+		Token:   result.Token,
+		In:      totalIn.String(),
+		Out:     totalOut.String(),
+		Fee:     totalFee.String(),
+		Balance: result.ClosingBalance,
+	})
+
+	result.TotalIn = totalIn.String()
+	result.TotalOut = totalOut.String()
+	result.TotalFee = totalFee.String()
+	result.Balanced = balance.String() == result.ClosingBalance
+
+	return
+}
+
+// renderStatement - CSV with header, or JSON Lines that has a JSON object of every line
+func renderStatement(lines []StatementLine, format string) (string, error) {
+	buf := new(bytes.Buffer)
+
+	if format == statementFormatJSONL {
+		for _, line := range lines {
+			data, err := json.Marshal(line)
+			if err != nil {
+				return "", err
+			}
+			buf.Write(data)
+			buf.WriteByte('\n')
+		}
+
+		return buf.String(), nil
+	}
+
+	w := csv.NewWriter(buf)
+	if err := w.Write(statementCSVHeader); err != nil {
+		return "", err
+	}
+	for _, line := range lines {
+		record := []string{line.Type, line.Time, line.TxHash, strconv.FormatInt(line.Height, 10), line.Counterparty,
+			line.Token, line.In, line.Out, line.Fee, line.Balance}
+		if err := w.Write(record); err != nil {
+			return "", err
+		}
+	}
+	w.Flush()
+
+	return buf.String(), w.Error()
+}
@@ -0,0 +1,65 @@
+package rpc
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatementLines(t *testing.T) {
+	assert := assert.New(t)
+
+	items := []HistoryItem{
+		{TxHash: "0x01", Height: 11, Direction: directionIn, Token: "bcbToken", Value: "1000", From: "bcbOther", To: testAddress},
+		{TxHash: "0x02", Height: 12, Direction: directionOut, Token: "bcbToken", Value: "300", From: testAddress, To: "bcbOther"},
+		{TxHash: "0x02", Height: 12, Direction: directionFee, Token: "bcbToken", Value: "50", From: testAddress},
+	}
+	timeOf := func(height int64) (time.Time, error) {
+		return time.Unix(1000+height, 0), nil
+	}
+
+	result := &StatementResult{Token: "bcbToken", FromHeight: 10, ToHeight: 20, OpeningBalance: "500", ClosingBalance: "1150"}
+	lines, err := statementLines(result, 1000, 2000, items, timeOf)
+	assert.Nil(err)
+	if assert.Equal(5, len(lines)) {
+		assert.Equal(statementOpening, lines[0].Type)
+		assert.Equal("500", lines[0].Balance)
+		assert.Equal("bcbOther", lines[1].Counterparty)
+		assert.Equal("1500", lines[1].Balance)
+		assert.Equal("1200", lines[2].Balance)
+		assert.Equal("50", lines[3].Fee)
+		assert.Equal("1150", lines[3].Balance)
+		assert.Equal(statementClosing, lines[4].Type)
+		assert.Equal("1150", lines[4].Balance)
+	}
+	assert.Equal("1000", result.TotalIn)
+	assert.Equal("300", result.TotalOut)
+	assert.Equal("50", result.TotalFee)
+	assert.True(result.Balanced)
+
+	// transfers missing in history are revealed by the closing balance
+	result = &StatementResult{Token: "bcbToken", OpeningBalance: "500", ClosingBalance: "2000"}
+	_, err = statementLines(result, 1000, 2000, items, timeOf)
+	assert.Nil(err)
+	assert.False(result.Balanced)
+
+	content, err := renderStatement(lines, statementFormatCSV)
+	assert.Nil(err)
+	rows := strings.Split(strings.TrimSpace(content), "\n")
+	if assert.Equal(6, len(rows)) {
+		assert.Equal(strings.Join(statementCSVHeader, ","), rows[0])
+		assert.Equal("out,1970-01-01T00:16:52Z,0x02,12,bcbOther,bcbToken,,300,,1200", rows[3])
+	}
+
+	content, err = renderStatement(lines, statementFormatJSONL)
+	assert.Nil(err)
+	rows = strings.Split(strings.TrimSpace(content), "\n")
+	if assert.Equal(5, len(rows)) {
+		var line StatementLine
+		assert.Nil(json.Unmarshal([]byte(rows[4]), &line))
+		assert.Equal(lines[4], line)
+	}
+}
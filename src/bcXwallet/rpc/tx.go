@@ -1,6 +1,7 @@
 package rpc
 
 import (
+	"bcXwallet/common"
 	"blockchain/abciapp_v1.0/keys"
 	"blockchain/abciapp_v1.0/prototype"
 	atm "blockchain/algorithm"
@@ -20,7 +21,7 @@ type BcbXTransaction struct {
 	Data     []byte       // 调用智能合约所需要的参数，RLP编码格式。
 }
 
-func PackAndSignTx(nonce, gasLimit uint64, note, tokenAddress, toAddress string, value []byte, name, accessKey string) (string, error) {
+func PackAndSignTx(c *common.Chain, nonce, gasLimit uint64, note, tokenAddress, toAddress string, value []byte, name, accessKey string) (string, error) {
 
 	var mi MethodInfo
 	var err error
@@ -45,7 +46,7 @@ func PackAndSignTx(nonce, gasLimit uint64, note, tokenAddress, toAddress string,
 	}
 
 	tx1 := NewTransaction(nonce, gasLimit, note, tokenAddress, data)
-	return tx1.TxGen(name, accessKey)
+	return tx1.TxGen(c, name, accessKey)
 }
 
 func NewTransaction(nonce uint64, gasLimit uint64, note string, to keys.Address, data []byte) BcbXTransaction {
@@ -61,7 +62,8 @@ func NewTransaction(nonce uint64, gasLimit uint64, note string, to keys.Address,
 
 // 定义生成交易的接口函数，其中tx.Data已经按RLP进行编码
 //返回构造好的交易数据，MAC.Version.Payload.<1>.Signature，Payload和Signature格式是RLP编码后的HexString
-func (tx *BcbXTransaction) TxGen(name, accessKey string) (string, error) {
+//MAC是链c的链ID，签名使用链c的钱包
+func (tx *BcbXTransaction) TxGen(c *common.Chain, name, accessKey string) (string, error) {
 	//RLP编码tx
 	size, r, err := rlp.EncodeToReader(tx)
 	if err != nil {
@@ -70,7 +72,7 @@ func (tx *BcbXTransaction) TxGen(name, accessKey string) (string, error) {
 	txBytes := make([]byte, size)
 	_, _ = r.Read(txBytes)
 
-	sigInfo, err := SignData(name, accessKey, txBytes)
+	sigInfo, err := SignData(c, name, accessKey, txBytes)
 	if err != nil {
 		return "", err
 	}
@@ -86,14 +88,14 @@ func (tx *BcbXTransaction) TxGen(name, accessKey string) (string, error) {
 	txString := base58.Encode(txBytes)
 	sigString := base58.Encode(sigBytes)
 
-	MAC := string(crypto.GetChainId()) + "<tx>"
+	MAC := c.ChainID + "<tx>"
 	Version := "v1"
 	SignerNumber := "<1>"
 
 	return MAC + "." + Version + "." + txString + "." + SignerNumber + "." + sigString, nil
 }
 
-func SignData(name, accessKey string, data []byte) (*types.Ed25519Sig, error) {
+func SignData(c *common.Chain, name, accessKey string, data []byte) (*types.Ed25519Sig, error) {
 	if name == "" || accessKey == "" {
 		return nil, errors.New("user name and accessKey cannot to te empty")
 	}
@@ -107,7 +109,7 @@ func SignData(name, accessKey string, data []byte) (*types.Ed25519Sig, error) {
 
 	accessKeyBytes := base58.Decode(accessKey)
 
-	acct, err2 := db.Account(name, accessKeyBytes)
+	acct, err2 := db.Account(c.KeyStoreNamespace, name, accessKeyBytes)
 	if acct == nil {
 		return nil, err2
 	}
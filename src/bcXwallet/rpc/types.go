@@ -1,12 +1,20 @@
 package rpc
 
 import (
+	"bcXwallet/common"
 	"blockchain/abciapp_v1.0/keys"
+	"encoding/json"
 )
 
 const transferMethodIDV1 = "af0228bc"
 const transferMethodIDV2 = "44d8ca60"
 
+// amount units, 1 token = 1000000000 cong
+const (
+	amountUnitCong  = "cong"
+	amountUnitToken = "token"
+)
+
 // ----- param struct ----
 type TransferParam struct {
 	SmcAddress keys.Address `json:"smcAddress"`
@@ -14,6 +22,7 @@ type TransferParam struct {
 	Note       string       `json:"note"`
 	To         keys.Address `json:"to"`
 	Value      string       `json:"value"`
+	AmountUnit string       `json:"amountUnit"`
 }
 
 type TransferOfflineParam struct {
@@ -23,6 +32,20 @@ type TransferOfflineParam struct {
 	Nonce      uint64       `json:"nonce"`
 	To         keys.Address `json:"to"`
 	Value      string       `json:"value"`
+	AmountUnit string       `json:"amountUnit"`
+}
+
+// MultisigParam - parameters of multi-signature transfer transaction
+type MultisigParam struct {
+	SmcAddress keys.Address   `json:"smcAddress"`
+	GasLimit   string         `json:"gasLimit"`
+	Note       string         `json:"note"`
+	Nonce      uint64         `json:"nonce"`
+	To         keys.Address   `json:"to"`
+	Value      string         `json:"value"`
+	AmountUnit string         `json:"amountUnit"`
+	Signers    []keys.Address `json:"signers"`
+	Threshold  int            `json:"threshold"`
 }
 
 // ----- result struct -----
@@ -58,11 +81,13 @@ type WalletItem struct {
 
 // TransferResult - transfer result
 type TransferResult struct {
-	Code   uint32 `json:"code"`
-	Log    string `json:"log"`
-	Fee    uint64 `json:"fee"`
-	TxHash string `json:"txHash"`
-	Height int64  `json:"height"`
+	Code       uint32 `json:"code"`
+	Log        string `json:"log"`
+	Fee        uint64 `json:"fee"`
+	FeeDecimal string `json:"feeDecimal,omitempty"`
+	Unit       string `json:"unit,omitempty"`
+	TxHash     string `json:"txHash"`
+	Height     int64  `json:"height"`
 }
 
 // TransferResult - transfer result
@@ -75,13 +100,91 @@ type BlockHeightResult struct {
 	LastBlock int64 `json:"lastBlock"`
 }
 
+// StatusResult - chain and node status
+type StatusResult struct {
+	ChainID         string `json:"chainID"`
+	ChainVersion    int64  `json:"chainVersion"`
+	LatestHeight    int64  `json:"latestHeight"`
+	LatestBlockHash string `json:"latestBlockHash"`
+	LatestBlockTime string `json:"latestBlockTime"`
+	CatchingUp      bool   `json:"catchingUp"`
+	NodeVersion     string `json:"nodeVersion"`
+}
+
+// ChainVersionResult - chain version that transactions are encoded with, source is health, block or config,
+// height is of the block where it was seen
+type ChainVersionResult struct {
+	ChainVersion  string `json:"chainVersion"`
+	Source        string `json:"source"`
+	Height        int64  `json:"height"`
+	RefreshedTime int64  `json:"refreshedTime"`
+	ConfigVersion string `json:"configVersion"`
+}
+
+// ValidatorResult - validator struct
+type ValidatorResult struct {
+	Address    keys.Address `json:"address"`
+	PubKey     string       `json:"pubKey"`
+	Power      uint64       `json:"power"`
+	RewardAddr keys.Address `json:"rewardAddr"`
+	Name       string       `json:"name"`
+}
+
+// ValidatorsResult - validators of height
+type ValidatorsResult struct {
+	Height     int64             `json:"height"`
+	Validators []ValidatorResult `json:"validators"`
+}
+
+// PeerResult - peer of node
+type PeerResult struct {
+	ID         string `json:"id"`
+	Moniker    string `json:"moniker"`
+	ListenAddr string `json:"listenAddr"`
+	Version    string `json:"version"`
+	IsOutbound bool   `json:"isOutbound"`
+}
+
+// NetInfoResult - network info of node
+type NetInfoResult struct {
+	Listening bool         `json:"listening"`
+	PeerCount int          `json:"peerCount"`
+	Peers     []PeerResult `json:"peers"`
+}
+
+// PendingTxResult - transaction in mempool of node, direction is incoming, outgoing or self
+type PendingTxResult struct {
+	TxHash    string       `json:"txHash"`
+	From      keys.Address `json:"from"`
+	Nonce     uint64       `json:"nonce"`
+	GasLimit  uint64       `json:"gasLimit"`
+	Note      string       `json:"note"`
+	Messages  []Message    `json:"messages"`
+	Direction string       `json:"direction"`
+}
+
+// PendingTxsResult - pending transactions of addresses, total is count of all transactions in mempool
+type PendingTxsResult struct {
+	Total int               `json:"total"`
+	Count int               `json:"count"`
+	Txs   []PendingTxResult `json:"txs"`
+}
+
 // Message - message struct
 type Message struct {
-	SmcAddress keys.Address `json:"smcAddress"`
-	SmcName    string       `json:"smcName"`
-	Method     string       `json:"method"`
-	To         string       `json:"to"`
-	Value      string       `json:"value"`
+	SmcAddress   keys.Address `json:"smcAddress"`
+	SmcName      string       `json:"smcName"`
+	Method       string       `json:"method"`
+	To           string       `json:"to"`
+	Value        string       `json:"value"`
+	ValueDecimal string       `json:"valueDecimal,omitempty"`
+	Params       []Param      `json:"params"`
+}
+
+// Param - decoded parameter of contract method, value is hex when type is unknown
+type Param struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
 }
 
 // TxResult - transaction struct
@@ -96,8 +199,28 @@ type TxResult struct {
 	Nonce       uint64       `json:"nonce"`
 	GasLimit    uint64       `json:"gasLimit"`
 	Fee         uint64       `json:"fee"`
+	FeeDecimal  string       `json:"feeDecimal,omitempty"`
+	Unit        string       `json:"unit,omitempty"`
 	Note        string       `json:"note"`
 	Messages    []Message    `json:"messages"`
+	Receipts    []Receipt    `json:"receipts"`
+}
+
+// Receipt - execution receipt of transaction, unknown receipt keeps raw bytes and hash in hex
+type Receipt struct {
+	Name         string       `json:"name"`
+	ContractAddr keys.Address `json:"contractAddress"`
+	Token        keys.Address `json:"token,omitempty"`
+	From         keys.Address `json:"from,omitempty"`
+	To           keys.Address `json:"to,omitempty"`
+	Value        string       `json:"value,omitempty"`
+	ValueDecimal string       `json:"valueDecimal,omitempty"`
+	TotalSupply  string       `json:"totalSupply,omitempty"`
+	SmcAddress   keys.Address `json:"smcAddress,omitempty"`
+	NewOwner     keys.Address `json:"newOwner,omitempty"`
+	GasPrice     string       `json:"gasPrice,omitempty"`
+	Bytes        string       `json:"receiptBytes,omitempty"`
+	Hash         string       `json:"receiptHash,omitempty"`
 }
 
 // BlockResult - block struct
@@ -112,9 +235,6 @@ type BlockResult struct {
 	BlockSize       int          `json:"blockSize,omitempty"`
 	ProposerAddress keys.Address `json:"proposerAddress,omitempty"`
 	Txs             []TxResult   `json:"txs,omitempty"`
-
-	// simple result contain several blocks
-	Result []SimpleBlockResult `json:"result,omitempty"`
 }
 
 // SimpleBlockResult simple block information contain height,hash and time
@@ -122,18 +242,116 @@ type SimpleBlockResult struct {
 	BlockHeight int64  `json:"blockHeight"`
 	BlockHash   string `json:"blockHash"`
 	BlockTime   string `json:"blockTime"`
+	TxCount     int64  `json:"txCount"`
+}
+
+// BlocksResult - blocks in height range, headers only in result or decoded blocks in blocks,
+// query again from nextHeight if it is not zero
+type BlocksResult struct {
+	LastHeight int64               `json:"lastHeight"`
+	NextHeight int64               `json:"nextHeight"`
+	Result     []SimpleBlockResult `json:"result,omitempty"`
+	Blocks     []BlockResult       `json:"blocks,omitempty"`
 }
 
 // BalanceResult - balance struct
 type BalanceResult struct {
-	Balance string `json:"balance"`
+	Balance        string `json:"balance"`
+	BalanceDecimal string `json:"balanceDecimal,omitempty"`
+	Unit           string `json:"unit,omitempty"`
+}
+
+// BalanceAtTimeResult - balance at the last block that is not later than timestamp
+type BalanceAtTimeResult struct {
+	Height         int64  `json:"height"`
+	BlockTime      string `json:"blockTime"`
+	Balance        string `json:"balance"`
+	BalanceDecimal string `json:"balanceDecimal,omitempty"`
+	Unit           string `json:"unit,omitempty"`
 }
 
 // AllBalanceItemResult - item of all balance struct
 type AllBalanceItemResult struct {
-	TokenAddress keys.Address `json:"tokenAddress"`
-	TokenName    string       `json:"tokenName"`
-	Balance      string       `json:"balance"`
+	TokenAddress   keys.Address `json:"tokenAddress"`
+	TokenName      string       `json:"tokenName"`
+	Balance        string       `json:"balance"`
+	BalanceDecimal string       `json:"balanceDecimal,omitempty"`
+	Unit           string       `json:"unit,omitempty"`
+}
+
+// TokenInfoResult - token with the contract that is effective now
+type TokenInfoResult struct {
+	Address          keys.Address `json:"address"`
+	Owner            keys.Address `json:"owner"`
+	Name             string       `json:"name"`
+	Symbol           string       `json:"symbol"`
+	TotalSupply      string       `json:"totalSupply"`
+	AddSupplyEnabled bool         `json:"addSupplyEnabled"`
+	BurnEnabled      bool         `json:"burnEnabled"`
+	GasPrice         int64        `json:"gasPrice"`
+	ContractAddress  keys.Address `json:"contractAddress"`
+	ContractVersion  string       `json:"contractVersion"`
+}
+
+// TokensResult - all tokens of chain
+type TokensResult struct {
+	Tokens []TokenInfoResult `json:"tokens"`
+}
+
+// MethodResult - method of contract with its gas cost
+type MethodResult struct {
+	MethodID  string `json:"methodId"`
+	Gas       int64  `json:"gas"`
+	ProtoType string `json:"prototype"`
+}
+
+// ContractResult - contract of address with the version of it that is effective at height
+type ContractResult struct {
+	Address          keys.Address   `json:"address"`
+	Account          keys.Address   `json:"account"`
+	Owner            keys.Address   `json:"owner"`
+	Name             string         `json:"name"`
+	Version          string         `json:"version"`
+	CodeHash         string         `json:"codeHash"`
+	EffectHeight     int64          `json:"effectHeight"`
+	LoseHeight       int64          `json:"loseHeight"`
+	Methods          []MethodResult `json:"methods"`
+	Interfaces       []MethodResult `json:"interfaces"`
+	Token            keys.Address   `json:"token"`
+	OrgID            string         `json:"orgID"`
+	ChainVersion     int64          `json:"chainVersion"`
+	Height           int64          `json:"height"`
+	Effective        bool           `json:"effective"`
+	EffectiveAddress keys.Address   `json:"effectiveAddress"`
+	EffectiveVersion string         `json:"effectiveVersion"`
+}
+
+// ContractVersionResult - one version of contract
+type ContractVersionResult struct {
+	Address      keys.Address `json:"address"`
+	Version      string       `json:"version"`
+	EffectHeight int64        `json:"effectHeight"`
+	LoseHeight   int64        `json:"loseHeight"`
+}
+
+// ContractVersionsResult - versions of contract with the one that is effective at height
+type ContractVersionsResult struct {
+	OrgID            string                  `json:"orgID"`
+	Name             string                  `json:"name"`
+	Height           int64                   `json:"height"`
+	Versions         []ContractVersionResult `json:"versions"`
+	EffectiveAddress keys.Address            `json:"effectiveAddress"`
+	EffectiveVersion string                  `json:"effectiveVersion"`
+}
+
+// OrganizationResult - organization with its contracts and signers
+type OrganizationResult struct {
+	OrgID            string         `json:"orgID"`
+	Name             string         `json:"name"`
+	OrgOwner         keys.Address   `json:"orgOwner"`
+	ContractAddrList []keys.Address `json:"contractAddrList"`
+	OrgCodeHash      string         `json:"orgCodeHash"`
+	Signers          []string       `json:"signers"`
 }
 
 // NonceResult - nonce struct
@@ -150,6 +368,218 @@ type CommitTxResult struct {
 	Height int64  `json:"height"`
 }
 
+// MultisigSignature - signature of one signer of multi-signature transaction
+type MultisigSignature struct {
+	Signer    keys.Address `json:"signer"`
+	PubKey    string       `json:"pubKey"`
+	Signature string       `json:"signature"`
+}
+
+// MultisigTxResult - multi-signature transaction and collected signatures, it's the exchanged document between signers
+type MultisigTxResult struct {
+	ID         string              `json:"id"`
+	ChainID    string              `json:"chainID"`
+	Payload    string              `json:"payload"`
+	Nonce      uint64              `json:"nonce"`
+	GasLimit   int64               `json:"gasLimit"`
+	Note       string              `json:"note"`
+	Messages   []Message           `json:"messages"`
+	Signers    []keys.Address      `json:"signers"`
+	Threshold  int                 `json:"threshold"`
+	Signatures []MultisigSignature `json:"signatures"`
+}
+
+// MultisigSignersResult - signed and unsigned signers of multi-signature transaction
+type MultisigSignersResult struct {
+	ID        string         `json:"id"`
+	Threshold int            `json:"threshold"`
+	Signed    []keys.Address `json:"signed"`
+	Unsigned  []keys.Address `json:"unsigned"`
+	Ready     bool           `json:"ready"`
+}
+
+// MultisigFinalizeResult - multi-signature transaction with all collected signatures
+type MultisigFinalizeResult struct {
+	Tx     string `json:"tx"`
+	TxHash string `json:"txHash"`
+}
+
+// SignedQueryResult - value of signed query, data is the decoded value if it's JSON
+type SignedQueryResult struct {
+	Key   string          `json:"key"`
+	Value string          `json:"value"`
+	Data  json.RawMessage `json:"data,omitempty"`
+}
+
+// HistoryItem - transfer or fee of address, direction is in, out or fee
+type HistoryItem struct {
+	TxHash    string       `json:"txHash"`
+	Height    int64        `json:"height"`
+	Direction string       `json:"direction"`
+	Token     keys.Address `json:"token"`
+	Value     string       `json:"value"`
+	From      keys.Address `json:"from"`
+	To        keys.Address `json:"to,omitempty"`
+}
+
+// AddressHistoryResult - history of address, query again with nextCursor if it is not empty
+type AddressHistoryResult struct {
+	Address       keys.Address  `json:"address"`
+	IndexedHeight int64         `json:"indexedHeight"`
+	Items         []HistoryItem `json:"items"`
+	NextCursor    string        `json:"nextCursor"`
+}
+
+// StatementLine - line of statement, type is opening, in, out, fee or closing,
+// the closing line has totals of in, out and fee
+type StatementLine struct {
+	Type         string       `json:"type"`
+	Time         string       `json:"time"`
+	TxHash       string       `json:"txHash,omitempty"`
+	Height       int64        `json:"height"`
+	Counterparty keys.Address `json:"counterparty,omitempty"`
+	Token        keys.Address `json:"token"`
+	In           string       `json:"in,omitempty"`
+	Out          string       `json:"out,omitempty"`
+	Fee          string       `json:"fee,omitempty"`
+	Balance      string       `json:"balance"`
+}
+
+// StatementResult - statement of address in token, content is CSV or JSON Lines of statement lines,
+// balanced is true if the running balance ends with the on-chain closing balance
+type StatementResult struct {
+	Address        keys.Address `json:"address"`
+	Token          keys.Address `json:"token"`
+	FromHeight     int64        `json:"fromHeight"`
+	ToHeight       int64        `json:"toHeight"`
+	OpeningBalance string       `json:"openingBalance"`
+	ClosingBalance string       `json:"closingBalance"`
+	TotalIn        string       `json:"totalIn"`
+	TotalOut       string       `json:"totalOut"`
+	TotalFee       string       `json:"totalFee"`
+	Balanced       bool         `json:"balanced"`
+	Format         string       `json:"format"`
+	Content        string       `json:"content"`
+}
+
+// ReconcileToken - balance change of token between snapshots against transfers and fees in address history,
+// discrepancy is change - (in - out - fee)
+type ReconcileToken struct {
+	Token           keys.Address `json:"token"`
+	TokenName       string       `json:"tokenName"`
+	PreviousBalance string       `json:"previousBalance"`
+	Balance         string       `json:"balance"`
+	Change          string       `json:"change"`
+	In              string       `json:"in"`
+	Out             string       `json:"out"`
+	Fee             string       `json:"fee"`
+	Discrepancy     string       `json:"discrepancy"`
+}
+
+// ReconcileWallet - balances of wallet in snapshot, baseline is true if it's the first snapshot of wallet
+// and nothing is compared
+type ReconcileWallet struct {
+	Name     string           `json:"name"`
+	Address  keys.Address     `json:"address"`
+	Baseline bool             `json:"baseline"`
+	Balanced bool             `json:"balanced"`
+	Tokens   []ReconcileToken `json:"tokens"`
+}
+
+// ReconcileReportResult - snapshot of all wallets at height compared with the previous one,
+// discrepancies is count of tokens with discrepancy
+type ReconcileReportResult struct {
+	Height         int64             `json:"height"`
+	PreviousHeight int64             `json:"previousHeight"`
+	Time           int64             `json:"time"`
+	Discrepancies  int               `json:"discrepancies"`
+	Wallets        []ReconcileWallet `json:"wallets"`
+}
+
+// Deposit - incoming transfer of wallet in keystore or address in watch list
+type Deposit struct {
+	ID            uint64       `json:"id"`
+	TxHash        string       `json:"txHash"`
+	Height        int64        `json:"height"`
+	Address       keys.Address `json:"address"`
+	Token         keys.Address `json:"token"`
+	Value         string       `json:"value"`
+	From          keys.Address `json:"from"`
+	Confirmations int64        `json:"confirmations"`
+	Confirmed     bool         `json:"confirmed"`
+	Acked         bool         `json:"acked"`
+}
+
+// DepositsResult - deposits that are not acked, query again with lastId as sinceId
+type DepositsResult struct {
+	ScannedHeight int64     `json:"scannedHeight"`
+	Deposits      []Deposit `json:"deposits"`
+	LastID        uint64    `json:"lastId"`
+}
+
+// WebhookEvent - event posted to webhook urls, chain is the chain that it happened on and empty for events of nodes,
+// data is JSON text of the event's object
+type WebhookEvent struct {
+	ID    uint64 `json:"id"`
+	Type  string `json:"type"`
+	Chain string `json:"chain,omitempty"`
+	Time  int64  `json:"time"`
+	Data  string `json:"data"`
+}
+
+// TransferEvent - data of transfer committed or failed event
+type TransferEvent struct {
+	Name   string       `json:"name"`
+	Token  keys.Address `json:"token"`
+	To     keys.Address `json:"to"`
+	Value  string       `json:"value"`
+	Code   uint32       `json:"code"`
+	Log    string       `json:"log"`
+	TxHash string       `json:"txHash"`
+	Height int64        `json:"height"`
+}
+
+// NodeEvent - data of node unreachable event
+type NodeEvent struct {
+	NodeAddr string `json:"nodeAddr"`
+	Error    string `json:"error"`
+}
+
+// SubscribeResult - topic of websocket subscription
+type SubscribeResult struct {
+	Topic string `json:"topic,omitempty"`
+}
+
+// WebsocketEvent - block of newBlock topic, or transaction of walletTx and txStatus topics on chain
+type WebsocketEvent struct {
+	Topic string       `json:"topic"`
+	Chain string       `json:"chain"`
+	Block *BlockResult `json:"block,omitempty"`
+	Tx    *TxResult    `json:"tx,omitempty"`
+}
+
+// NodesResult - state of nodes in pool, and counters of quorum reads
+type NodesResult struct {
+	Nodes             []common.NodeStatus `json:"nodes"`
+	QuorumSize        int                 `json:"quorumSize"`
+	QuorumReads       uint64              `json:"quorumReads"`
+	QuorumDivergences uint64              `json:"quorumDivergences"`
+}
+
+// MetaCacheStatsResult - size and hit/miss counters of metadata cache
+type MetaCacheStatsResult struct {
+	Size     int    `json:"size"`
+	Capacity int    `json:"capacity"`
+	Hits     uint64 `json:"hits"`
+	Misses   uint64 `json:"misses"`
+}
+
+// ConvertUnitResult - amount in cong and token
+type ConvertUnitResult struct {
+	Cong  string `json:"cong"`
+	Token string `json:"token"`
+}
+
 // VersionResult - version struct
 type VersionResult struct {
 	Version string `json:"version"`
@@ -11,8 +11,11 @@ import (
 	"errors"
 	"fmt"
 	"github.com/btcsuite/btcutil/base58"
+	types2 "github.com/tendermint/abci/types"
 	"github.com/tendermint/go-amino"
 	"github.com/tendermint/go-crypto"
+	"math/big"
+	"strconv"
 	"strings"
 )
 
@@ -22,16 +25,16 @@ const (
 
 var cdc = amino.NewCodec()
 
-func walletCreate(name, password string) (result *WalletCreateResult, err error) {
+func walletCreate(c *common.Chain, name, password string) (result *WalletCreateResult, err error) {
 	logger := common.GetLogger()
 
-	acct, accessKey, err := newAccount(name, password)
+	acct, accessKey, err := newAccount(c, name, password)
 	if err != nil {
 		logger.Info(err.Error())
 		return
 	}
 
-	err = acct.Save(accessKey)
+	err = acct.Save(c.KeyStoreNamespace, accessKey)
 	if err != nil {
 		return
 	}
@@ -44,11 +47,11 @@ func walletCreate(name, password string) (result *WalletCreateResult, err error)
 	return
 }
 
-func walletExport(name, password, accessKey string, plainText bool) (result *WalletExportResult, err error) {
+func walletExport(c *common.Chain, name, password, accessKey string, plainText bool) (result *WalletExportResult, err error) {
 
 	accessKeyBytes := base58.Decode(accessKey)
 
-	acct, err := db.Account(name, accessKeyBytes)
+	acct, err := chainAccount(c, name, accessKeyBytes)
 	if err != nil {
 		fmt.Println("Failed to get account from database, please name or accessKey.")
 		return
@@ -71,11 +74,11 @@ func walletExport(name, password, accessKey string, plainText bool) (result *Wal
 	return
 }
 
-func walletImport(name, privateKey, password, accessKey string, plainText bool) (result *WalletImportResult, err error) {
+func walletImport(c *common.Chain, name, privateKey, password, accessKey string, plainText bool) (result *WalletImportResult, err error) {
 
 	result = new(WalletImportResult)
 
-	isExist, _ := db.IsExist(name)
+	isExist, _ := db.IsExist(c.KeyStoreNamespace, name)
 	if isExist {
 		return nil, errors.New("The account of " + name + " is already exist!")
 	}
@@ -105,9 +108,8 @@ func walletImport(name, privateKey, password, accessKey string, plainText bool)
 
 	encPrivateKey := algorithm.EncryptWithPassword(priKeyBytes, []byte(password), accessKeyBytes)
 
-	cfg := common.GetConfig()
 	priKey := crypto.PrivKeyEd25519FromBytes(priKeyBytes)
-	address := priKey.PubKey().Address(cfg.ChainID)
+	address := priKey.PubKey().Address(c.ChainID)
 
 	acct := Account{
 		Name:          name,
@@ -116,7 +118,7 @@ func walletImport(name, privateKey, password, accessKey string, plainText bool)
 		PrivateKey:    priKeyBytes,
 	}
 
-	err = acct.Save(accessKeyBytes)
+	err = acct.Save(c.KeyStoreNamespace, accessKeyBytes)
 	if err != nil {
 		return
 	}
@@ -127,17 +129,17 @@ func walletImport(name, privateKey, password, accessKey string, plainText bool)
 	return
 }
 
-func walletList(pageNum uint64) (*WalletListResult, error) {
+func walletList(c *common.Chain, pageNum uint64) (*WalletListResult, error) {
 	wallet := new(WalletListResult)
 	wallet.WalletList = make([]WalletItem, 0)
 
 	var err error
-	wallet.Total, err = db.AccountNumber()
+	wallet.Total, err = db.AccountNumber(c.KeyStoreNamespace)
 	if err != nil {
 		return nil, err
 	}
 
-	walletList, err := db.WalletList(pageNum)
+	walletList, err := db.WalletList(c.KeyStoreNamespace, pageNum)
 	if err != nil {
 		return nil, err
 	}
@@ -153,101 +155,147 @@ func walletList(pageNum uint64) (*WalletListResult, error) {
 	return wallet, err
 }
 
-func transfer(name, accessKey string, gasLimit uint64, walletParams TransferParam) (result *TransferResult, err error) {
+// walletAddresses - addresses of all wallets in keystore namespace of chain by name, wallets of namespace
+// all belong to the same chain ID so the saved addresses are of chain
+func walletAddresses(c *common.Chain) (map[string]types.Address, error) {
+	addresses := make(map[string]types.Address)
 
-	config := common.GetConfig()
-	result = new(TransferResult)
+	acctNumber, err := db.AccountNumber(c.KeyStoreNamespace)
+	if err != nil {
+		return nil, err
+	}
 
-	accessKeyBytes := base58.Decode(accessKey)
+	for pageNumber := uint64(1); pageNumber <= acctNumber/countOfOnePage+1; pageNumber++ {
+		walletList, err := db.WalletList(c.KeyStoreNamespace, pageNumber)
+		if err != nil {
+			return nil, err
+		}
 
-	acct, err := db.Account(name, accessKeyBytes)
-	if err != nil {
-		return
+		for _, walletItem := range walletList {
+			info := strings.Split(walletItem, "#")
+			addresses[info[0]] = info[len(info)-1]
+		}
 	}
 
-	// 获取nonce
-	nonceResult, err := nonce(acct.Address)
+	return addresses, nil
+}
+
+func transfer(c *common.Chain, name, accessKey string, gasLimit uint64, value *big.Int, walletParams TransferParam, requestID string) (result *TransferResult, err error) {
+
+	genTx := func() (string, error) {
+		return signTransfer(c, name, accessKey, gasLimit, value, walletParams)
+	}
+
+	var commit *CommitTxResult
+	var broadcast bool
+	if requestID == "" {
+		var txStr string
+		if txStr, err = genTx(); err != nil {
+			return
+		}
+		commit, err = commitTx(c, txStr)
+		broadcast = true
+	} else {
+		paramsHash := hashOfParams("bcb_transfer", c.Name, name, walletParams.SmcAddress, strconv.FormatUint(gasLimit, 10), walletParams.Note, walletParams.To, value.String())
+		commit, broadcast, err = commitWithRequestID(c, requestID, "bcb_transfer", paramsHash, genTx)
+	}
+	if broadcast && commit != nil {
+		notifyTransfer(c, name, value, walletParams, commit)
+	}
 	if err != nil {
 		return
 	}
 
-	value := bignumber.NewNumberString(walletParams.Value)
+	result = new(TransferResult)
+	result.Code = commit.Code
+	result.Log = commit.Log
+	result.Fee = commit.Fee
+	result.Height = commit.Height
+	result.TxHash = commit.TxHash
 
-	var txStr string
+	return
+}
 
-	if config.ChainVersion == "1" {
-		txStr, err = PackAndSignTx(nonceResult.Nonce, gasLimit, walletParams.Note, walletParams.SmcAddress, walletParams.To, value.Bytes(), name, accessKey)
-		if err != nil {
-			return nil, err
-		}
-	} else if config.ChainVersion == "2" {
-		var method uint32 = 0x44D8CA60
-		v := bn.NewNumberStringBase(walletParams.Value, 10)
-		V2Paramss := []interface{}{walletParams.To, v}
-		prikey := "0x" + hex.EncodeToString(acct.PrivateKey)
+// notifyTransfer - emit committed event of transfer on chain broadcast by this call, or failed event if its code is not ok,
+// transfers that are not broadcast, replays of requestId and broadcasts without result emit nothing
+func notifyTransfer(c *common.Chain, name string, value *big.Int, walletParams TransferParam, commit *CommitTxResult) {
 
-		txStr = GenerateTx(walletParams.SmcAddress, method, V2Paramss, nonceResult.Nonce, int64(gasLimit), walletParams.Note, prikey)
+	event := TransferEvent{Name: name, Token: walletParams.SmcAddress, To: walletParams.To, Value: value.String()}
+	event.Code, event.Log, event.TxHash, event.Height = commit.Code, commit.Log, commit.TxHash, commit.Height
+	if commit.Code != types2.CodeTypeOK {
+		emitEvent(c, eventTransferFailed, event)
 	} else {
-		return nil, errors.New("ChainVersion wrong, please check!")
+		emitEvent(c, eventTransferCommitted, event)
 	}
+}
+
+// signTransfer - pack and sign transfer transaction with the next nonce of wallet
+func signTransfer(c *common.Chain, name, accessKey string, gasLimit uint64, value *big.Int, walletParams TransferParam) (txStr string, err error) {
+
+	accessKeyBytes := base58.Decode(accessKey)
 
-	commitResult, err := common.DoHttpRequestAndParse(config.NodeAddrSlice, txStr)
+	acct, err := chainAccount(c, name, accessKeyBytes)
 	if err != nil {
 		return
 	}
 
-	if commitResult.CheckTx.Code != 200 {
-		result.Log = commitResult.CheckTx.Log
-		result.Code = commitResult.CheckTx.Code
-	} else {
-		result.Log = commitResult.DeliverTx.Log
-		result.Code = commitResult.DeliverTx.Code
+	// 获取nonce
+	nonceResult, err := nonce(c, acct.Address, 0)
+	if err != nil {
+		return
 	}
-	result.Fee = commitResult.DeliverTx.Fee
-	result.Height = commitResult.Height
-	result.TxHash = "0x" + hex.EncodeToString(commitResult.Hash)
 
-	return
+	return packTransferTx(c, acct, name, accessKey, nonceResult.Nonce, gasLimit, walletParams.Note, walletParams.SmcAddress, walletParams.To, value)
 }
 
-func walletTransferOffline(name, accessKey string, gasLimit uint64, walletParams TransferOfflineParam) (result *TransferOfflineResult, err error) {
-
-	config := common.GetConfig()
-	value := bignumber.NewNumberString(walletParams.Value)
+func walletTransferOffline(c *common.Chain, name, accessKey string, gasLimit uint64, value *big.Int, walletParams TransferOfflineParam) (result *TransferOfflineResult, err error) {
 
 	accessKeyBytes := base58.Decode(accessKey)
 
-	acct, err := db.Account(name, accessKeyBytes)
+	acct, err := chainAccount(c, name, accessKeyBytes)
 	if err != nil {
 		return
 	}
 
-	var txStr string
+	txStr, err := packTransferTx(c, acct, name, accessKey, walletParams.Nonce, gasLimit, walletParams.Note, walletParams.SmcAddress, walletParams.To, value)
+	if err != nil {
+		return nil, err
+	}
 
-	if config.ChainVersion == "1" {
-		txStr, err = PackAndSignTx(walletParams.Nonce, gasLimit, walletParams.Note, walletParams.SmcAddress, walletParams.To, value.Bytes(), name, accessKey)
-		if err != nil {
-			return nil, err
-		}
-	} else if config.ChainVersion == "2" {
+	result = new(TransferOfflineResult)
+	result.Tx = txStr
+
+	return
+}
+
+// packTransferTx - pack and sign transfer transaction, value is an integer count of cong without upper limit
+func packTransferTx(c *common.Chain, acct *Account, name, accessKey string, nonce, gasLimit uint64, note string, smcAddress, to types.Address, value *big.Int) (txStr string, err error) {
+
+	// the chain may be upgraded while wallet is running, so encoder is picked with the current chain version
+	chainVersion, err := c.ChainVersion()
+	if err != nil {
+		return
+	}
+
+	if chainVersion == "1" {
+		v := bignumber.NB(new(big.Int).Set(value))
+		txStr, err = PackAndSignTx(c, nonce, gasLimit, note, smcAddress, to, v.Bytes(), name, accessKey)
+	} else if chainVersion == "2" {
 		var method uint32 = 0x44D8CA60
-		v := bn.NewNumberStringBase(walletParams.Value, 10)
-		V2Paramss := []interface{}{walletParams.To, v}
+		v := bn.NString(value.String())
+		V2Paramss := []interface{}{to, v}
 		prikey := "0x" + hex.EncodeToString(acct.PrivateKey)
 
-		txStr = GenerateTx(walletParams.SmcAddress, method, V2Paramss, walletParams.Nonce, int64(gasLimit), walletParams.Note, prikey)
+		txStr = GenerateTx(c.ChainID, smcAddress, method, V2Paramss, nonce, int64(gasLimit), note, prikey)
 	} else {
-		return nil, errors.New("ChainVersion wrong, please check!")
+		err = errors.New("ChainVersion wrong, please check!")
 	}
 
-	result = new(TransferOfflineResult)
-	result.Tx = txStr
-
 	return
 }
 
-//GenerateTx generate tx with one contract method request
-func GenerateTx(contract types.Address, method uint32, V2Paramss []interface{}, nonce uint64, gaslimit int64, note string, privKey string) string {
+//GenerateTx generate tx of chain with one contract method request
+func GenerateTx(chainID string, contract types.Address, method uint32, V2Paramss []interface{}, nonce uint64, gaslimit int64, note string, privKey string) string {
 	items := tx2.WrapInvokeParams(V2Paramss...)
 	message := types.Message{
 		Contract: contract,
@@ -255,5 +303,5 @@ func GenerateTx(contract types.Address, method uint32, V2Paramss []interface{},
 		Items:    items,
 	}
 	payload := tx2.WrapPayload(nonce, gaslimit, note, message)
-	return tx2.WrapTx(payload, privKey)
+	return tx2.WrapTxOfChain(chainID, payload, privKey)
 }
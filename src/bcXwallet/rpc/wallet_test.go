@@ -0,0 +1,154 @@
+package rpc
+
+import (
+	"bcXwallet/common"
+	"bcXwallet/common/config"
+	tx3 "blockchain/abciapp_v1.0/tx/tx"
+	"blockchain/smcsdk/sdk/bn"
+	"blockchain/smcsdk/sdk/rlp"
+	"blockchain/tx2"
+	"common/bignumber_v1.0"
+	"testing"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/stretchr/testify/assert"
+	"github.com/tendermint/go-crypto"
+)
+
+const (
+	testChainID = "bcb"
+	testPrivKey = "0x4a2c14697282e658b3ed7dd5324de1a102d216d6fa50d5937ffe89f35cbc12aa68eb9a09813bdf7c0869bf34a244cc545711509fe70f978d121afd3a4ae610e6"
+	testAddress = "bcbKvG4ayU644JD7BHhEVmP5sof2Lekopj5K"
+)
+
+var testChain = common.NewChain(config.ChainProfile{Name: testChainID, ChainID: testChainID})
+
+var uint64Boundary = []string{
+	"18446744073709551614",
+	"18446744073709551615",
+	"18446744073709551616",
+	"340282366920938463463374607431768211456",
+}
+
+func TestRequireUint(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, v := range uint64Boundary {
+		value, err := requireUint(v)
+		if assert.Nil(err, v) {
+			assert.Equal(v, value.String())
+		}
+	}
+
+	for _, v := range []string{"", "-1", "+1", "1.0", "0x10", "1e20", " 1"} {
+		_, err := requireUint(v)
+		assert.NotNil(err, v)
+	}
+}
+
+func TestTransferValueV2(t *testing.T) {
+	assert := assert.New(t)
+	tx2.Init(testChainID)
+	crypto.SetChainId(testChainID)
+
+	for _, v := range uint64Boundary {
+		value, err := requireUint(v)
+		assert.Nil(err)
+
+		txStr := GenerateTx(testChainID, testAddress, 0x44D8CA60, []interface{}{testAddress, bn.NString(value.String())}, 1, 500, "", testPrivKey)
+		transaction, _, err := tx2.TxParse(txStr)
+		if !assert.Nil(err, v) {
+			continue
+		}
+
+		var parsed bn.Number
+		assert.Nil(rlp.DecodeBytes(transaction.Messages[0].Items[1], &parsed))
+		assert.Equal(v, parsed.String())
+	}
+}
+
+func TestTransferValueV1(t *testing.T) {
+	assert := assert.New(t)
+	defer openTestDB(t)()
+
+	v1 := common.NewChain(config.ChainProfile{Name: testChainID, ChainID: testChainID, ChainVersion: "1"})
+	acct, accessKey, err := newAccount(v1, "alice", "Ab1@Cd2$")
+	assert.Nil(err)
+	assert.Nil(acct.Save(v1.KeyStoreNamespace, accessKey))
+
+	for _, v := range uint64Boundary {
+		value, err := requireUint(v)
+		assert.Nil(err)
+
+		txStr, err := packTransferTx(v1, acct, "alice", base58.Encode(accessKey), 1, 500, "", testAddress, testAddress, value)
+		if !assert.Nil(err, v) {
+			continue
+		}
+
+		var transaction tx3.Transaction
+		from, _, err := transaction.TxParse(testChainID, txStr)
+		assert.Nil(err)
+		assert.Equal(acct.Address, from)
+
+		var mi MethodInfo
+		assert.Nil(rlp.DecodeBytes(transaction.Data, &mi))
+		decoded := make([][]byte, 0)
+		assert.Nil(rlp.DecodeBytes(mi.ParamData, &decoded))
+		assert.Equal(testAddress, string(decoded[0]))
+		assert.Equal(v, bignumber.SetBytes(decoded[1]).String())
+	}
+}
+
+func TestChainAccount(t *testing.T) {
+	assert := assert.New(t)
+	defer openTestDB(t)()
+
+	side := common.NewChain(config.ChainProfile{Name: "side", ChainID: "side", KeyStoreNamespace: "side"})
+
+	acct, accessKey, err := newAccount(side, "alice", "Ab1@Cd2$")
+	assert.Nil(err)
+	assert.Nil(acct.Save(side.KeyStoreNamespace, accessKey))
+
+	// wallets of namespace are not seen in other namespaces
+	isExist, err := db.IsExist(side.KeyStoreNamespace, "alice")
+	assert.Nil(err)
+	assert.True(isExist)
+	isExist, _ = db.IsExist(testChain.KeyStoreNamespace, "alice")
+	assert.False(isExist)
+	names, err := db.WalletList(side.KeyStoreNamespace, 1)
+	assert.Nil(err)
+	assert.Equal([]string{"alice#" + string(acct.Address)}, names)
+	_, err = chainAccount(testChain, "alice", accessKey)
+	assert.NotNil(err)
+
+	// address is derived with chain ID of chain
+	loaded, err := chainAccount(side, "alice", accessKey)
+	assert.Nil(err)
+	assert.Equal(acct.Address, loaded.Address)
+	assert.Equal("side", loaded.Address[:4])
+
+	other := common.NewChain(config.ChainProfile{Name: "other", ChainID: "other", KeyStoreNamespace: "side"})
+	loaded, err = chainAccount(other, "alice", accessKey)
+	assert.Nil(err)
+	assert.Equal("other", loaded.Address[:5])
+}
+
+func TestGenerateTxOfChain(t *testing.T) {
+	assert := assert.New(t)
+
+	// transaction of other chain is made and parsed without changing chain ID of process
+	txStr := GenerateTx("side", testAddress, 0x44D8CA60, []interface{}{testAddress, bn.N(1)}, 1, 500, "", testPrivKey)
+	_, _, err := tx2.TxParseOfChain("side", txStr)
+	assert.Nil(err)
+	_, _, err = tx2.TxParseOfChain(testChainID, txStr)
+	assert.NotNil(err)
+}
+
+func TestKeyOfWallet(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal([]byte("alice"), keyOfWallet("", "alice"))
+	assert.Equal([]byte("/bcbXWallet/namespace/side/wallet/alice"), keyOfWallet("side", "alice"))
+	assert.NotEqual(keyOfAccountNumber(""), keyOfAccountNumber("side"))
+	assert.NotEqual(keyOfWalletList("", 1), keyOfWalletList("side", 1))
+}
@@ -0,0 +1,276 @@
+package rpc
+
+import (
+	"bcXwallet/common"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/tendermint/tmlibs/log"
+)
+
+// types of webhook event
+const (
+	eventDepositDetected   = "deposit.detected"
+	eventDepositConfirmed  = "deposit.confirmed"
+	eventTransferCommitted = "transfer.committed"
+	eventTransferFailed    = "transfer.failed"
+	eventNodeUnreachable   = "node.unreachable"
+
+	eventReconcileDiscrepancy = "reconcile.discrepancy"
+)
+
+const (
+	webhookInterval         = time.Second      // wait time after outbox is processed
+	webhookTimeout          = 10 * time.Second // timeout of one delivery
+	webhookDeliveriesOnce   = 100              // max count of deliveries processed in one round
+	webhookMinBackoff       = time.Second
+	webhookMaxBackoff       = time.Hour
+	nodeUnreachableInterval = time.Minute // min interval of unreachable events of the same node
+	webhookSignatureHeader  = "X-Bcb-Signature"
+	webhookEventIDHeader    = "X-Bcb-Event-Id"
+	webhookEventTypeHeader  = "X-Bcb-Event-Type"
+)
+
+// webhookDelivery - delivery of event to url in outbox, it's deleted after the url accepted the event
+type webhookDelivery struct {
+	EventID   uint64 `json:"eventId"`
+	Index     int    `json:"index"`
+	URL       string `json:"url"`
+	Attempts  int    `json:"attempts"`
+	NextTime  int64  `json:"nextTime"`
+	LastError string `json:"lastError"`
+}
+
+var (
+	webhookMtx    sync.Mutex
+	webhookWakeup = make(chan struct{}, 1)
+	webhookClient = &http.Client{Timeout: webhookTimeout}
+
+	nodeUnreachableMtx  sync.Mutex
+	nodeUnreachableTime = make(map[string]time.Time)
+)
+
+// StartNotifier - start webhook notifier if webhookUrls is not empty in config
+func StartNotifier() {
+	if !webhookEnabled() {
+		return
+	}
+
+	common.SetNodeErrorHandler(notifyNodeUnreachable)
+
+	go func() {
+		cfg := common.GetConfig()
+		for {
+			if err := deliverWebhooks(common.GetLogger(), time.Now(), cfg.WebhookSecret, cfg.WebhookMaxAttempts); err != nil {
+				common.GetLogger().Error("Cannot deliver webhook events", "error", err)
+			}
+
+			select {
+			case <-webhookWakeup:
+			case <-time.After(webhookInterval):
+			}
+		}
+	}()
+}
+
+func webhookEnabled() bool {
+	return len(common.GetConfig().WebhookURLs) != 0
+}
+
+// emitEvent - save event of chain with deliveries to all webhook urls, c is nil for events of nodes,
+// nothing is done if notifier is not enabled
+func emitEvent(c *common.Chain, eventType string, data interface{}) {
+	if !webhookEnabled() {
+		return
+	}
+
+	chain := ""
+	if c != nil {
+		chain = c.Name
+	}
+	if err := saveEvent(chain, eventType, data, common.GetConfig().WebhookURLs); err != nil {
+		common.GetLogger().Error("Cannot save webhook event", "type", eventType, "chain", chain, "error", err)
+	}
+}
+
+func saveEvent(chain, eventType string, data interface{}, urls []string) error {
+
+	jsonData, err := cdc.MarshalJSON(data)
+	if err != nil {
+		return err
+	}
+
+	webhookMtx.Lock()
+	defer webhookMtx.Unlock()
+
+	event := &WebhookEvent{Type: eventType, Chain: chain, Time: time.Now().Unix(), Data: string(jsonData)}
+	if err = db.SaveWebhookEvent(event, urls); err != nil {
+		return err
+	}
+	wakeupNotifier()
+
+	return nil
+}
+
+func wakeupNotifier() {
+	select {
+	case webhookWakeup <- struct{}{}:
+	default:
+	}
+}
+
+// notifyNodeUnreachable - emit unreachable event of node, at most once in nodeUnreachableInterval
+func notifyNodeUnreachable(nodeAddr string, err error) {
+	nodeUnreachableMtx.Lock()
+	if last, ok := nodeUnreachableTime[nodeAddr]; ok && time.Since(last) < nodeUnreachableInterval {
+		nodeUnreachableMtx.Unlock()
+		return
+	}
+	nodeUnreachableTime[nodeAddr] = time.Now()
+	nodeUnreachableMtx.Unlock()
+
+	emitEvent(nil, eventNodeUnreachable, NodeEvent{NodeAddr: nodeAddr, Error: err.Error()})
+}
+
+// deliverWebhooks - post due deliveries in outbox, failed delivery is retried with exponential backoff,
+// maxAttempts 0 means retry until it succeeds
+func deliverWebhooks(logger log.Logger, now time.Time, secret string, maxAttempts int) error {
+
+	deliveries, err := db.WebhookDeliveries(now.UnixNano(), webhookDeliveriesOnce)
+	if err != nil {
+		return err
+	}
+
+	for i := range deliveries {
+		delivery := &deliveries[i]
+
+		event, err := db.WebhookEvent(delivery.EventID)
+		if err != nil {
+			return err
+		}
+
+		if event != nil {
+			err = postWebhook(delivery.URL, secret, event)
+		}
+		if event == nil || err == nil {
+			if err = db.DeleteWebhookDelivery(delivery); err != nil {
+				return err
+			}
+			continue
+		}
+
+		delivery.Attempts++
+		delivery.LastError = err.Error()
+		if maxAttempts > 0 && delivery.Attempts >= maxAttempts {
+			logger.Error("Give up webhook delivery, replay it with bcb_webhookReplay", "id", event.ID, "url", delivery.URL, "error", err)
+			if err = db.DeleteWebhookDelivery(delivery); err != nil {
+				return err
+			}
+			continue
+		}
+
+		logger.Warn("Cannot deliver webhook event", "id", event.ID, "url", delivery.URL, "attempts", delivery.Attempts, "error", err)
+		delivery.NextTime = now.Add(webhookBackoff(delivery.Attempts)).UnixNano()
+		if err = db.SetWebhookDelivery(delivery); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// webhookBackoff - wait time before the next attempt, it doubles after each failed attempt
+func webhookBackoff(attempts int) time.Duration {
+	backoff := webhookMinBackoff
+	for i := 1; i < attempts && backoff < webhookMaxBackoff; i++ {
+		backoff *= 2
+	}
+
+	if backoff > webhookMaxBackoff {
+		backoff = webhookMaxBackoff
+	}
+
+	return backoff
+}
+
+// webhookBody - JSON body of event, data is embedded as JSON object
+func webhookBody(event *WebhookEvent) ([]byte, error) {
+	return json.Marshal(struct {
+		ID    uint64          `json:"id"`
+		Type  string          `json:"type"`
+		Chain string          `json:"chain,omitempty"`
+		Time  int64           `json:"time"`
+		Data  json.RawMessage `json:"data"`
+	}{event.ID, event.Type, event.Chain, event.Time, json.RawMessage(event.Data)})
+}
+
+// webhookSignature - hex of HMAC-SHA256 of body with secret
+func webhookSignature(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// postWebhook - post event to url, any status other than 2xx is failure
+func postWebhook(url, secret string, event *WebhookEvent) error {
+
+	body, err := webhookBody(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhookEventIDHeader, strconv.FormatUint(event.ID, 10))
+	req.Header.Set(webhookEventTypeHeader, event.Type)
+	req.Header.Set(webhookSignatureHeader, webhookSignature(secret, body))
+
+	resp, err := webhookClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook response status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// webhookReplay - deliver event of chain to all webhook urls again, events of nodes can be replayed with any chain
+func webhookReplay(c *common.Chain, id uint64) (result *WebhookEvent, err error) {
+
+	if !webhookEnabled() {
+		return nil, errors.New("Webhook notifier is not enabled ")
+	}
+
+	if result, err = db.WebhookEvent(id); err != nil {
+		return
+	}
+	if result == nil || (result.Chain != "" && result.Chain != c.Name) {
+		return nil, errors.New("Webhook event " + strconv.FormatUint(id, 10) + " does not exist on chain " + c.Name + " ")
+	}
+
+	for i, url := range common.GetConfig().WebhookURLs {
+		if err = db.SetWebhookDelivery(&webhookDelivery{EventID: id, Index: i, URL: url}); err != nil {
+			return
+		}
+	}
+	wakeupNotifier()
+
+	return
+}
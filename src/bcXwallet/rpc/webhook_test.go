@@ -0,0 +1,135 @@
+package rpc
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tendermint/tmlibs/log"
+)
+
+func TestDeliverWebhooks(t *testing.T) {
+	assert := assert.New(t)
+	defer openTestDB(t)()
+
+	const secret = "secret"
+
+	type received struct {
+		body      []byte
+		signature string
+		eventID   string
+	}
+	receivedCh := make(chan received, 10)
+	failures := 1
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if failures > 0 {
+			failures--
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		receivedCh <- received{body, r.Header.Get(webhookSignatureHeader), r.Header.Get(webhookEventIDHeader)}
+	}))
+	defer server.Close()
+
+	deposit := Deposit{ID: 1, TxHash: "0x01", Height: 3, Address: "bob", Token: "token", Value: "100", From: "alice"}
+	assert.Nil(saveEvent(testChain.Name, eventDepositDetected, deposit, []string{server.URL}))
+
+	// the first attempt fails, it's retried after backoff
+	now := time.Now()
+	assert.Nil(deliverWebhooks(log.NewNopLogger(), now, secret, 0))
+	deliveries, err := db.WebhookDeliveries(math.MaxInt64, 10)
+	assert.Nil(err)
+	if assert.Equal(1, len(deliveries)) {
+		assert.Equal(1, deliveries[0].Attempts)
+		assert.Equal(now.Add(webhookMinBackoff).UnixNano(), deliveries[0].NextTime)
+	}
+
+	// not due yet
+	assert.Nil(deliverWebhooks(log.NewNopLogger(), now, secret, 0))
+	assert.Equal(0, len(receivedCh))
+
+	assert.Nil(deliverWebhooks(log.NewNopLogger(), now.Add(webhookMinBackoff), secret, 0))
+	if assert.Equal(1, len(receivedCh)) {
+		r := <-receivedCh
+		assert.Equal("1", r.eventID)
+		assert.Equal(webhookSignature(secret, r.body), r.signature)
+
+		var body struct {
+			ID    uint64  `json:"id"`
+			Type  string  `json:"type"`
+			Chain string  `json:"chain"`
+			Data  Deposit `json:"data"`
+		}
+		assert.Nil(json.Unmarshal(r.body, &body))
+		assert.Equal(uint64(1), body.ID)
+		assert.Equal(eventDepositDetected, body.Type)
+		assert.Equal(testChain.Name, body.Chain)
+		assert.Equal("0x01", body.Data.TxHash)
+	}
+
+	deliveries, err = db.WebhookDeliveries(math.MaxInt64, 10)
+	assert.Nil(err)
+	assert.Equal(0, len(deliveries))
+
+	// delivery is dropped after max attempts, the event is kept for replay
+	failures = 1
+	assert.Nil(saveEvent("", eventNodeUnreachable, NodeEvent{NodeAddr: "http://127.0.0.1:1", Error: "refused"}, []string{server.URL}))
+	assert.Nil(deliverWebhooks(log.NewNopLogger(), now, secret, 1))
+	deliveries, err = db.WebhookDeliveries(math.MaxInt64, 10)
+	assert.Nil(err)
+	assert.Equal(0, len(deliveries))
+
+	event, err := db.WebhookEvent(2)
+	assert.Nil(err)
+	if assert.NotNil(event) {
+		assert.Equal(eventNodeUnreachable, event.Type)
+	}
+}
+
+func TestDeliverWebhooksBehindRetries(t *testing.T) {
+	assert := assert.New(t)
+	defer openTestDB(t)()
+
+	var posts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&posts, 1)
+	}))
+	defer server.Close()
+
+	// more deliveries than one round to a dead endpoint wait for retry
+	dead := httptest.NewServer(nil)
+	dead.Close()
+	now := time.Now()
+	for i := 0; i < webhookDeliveriesOnce+10; i++ {
+		assert.Nil(saveEvent("", eventNodeUnreachable, NodeEvent{NodeAddr: dead.URL, Error: "refused"}, []string{dead.URL}))
+	}
+	assert.Nil(deliverWebhooks(log.NewNopLogger(), now, "", 0))
+	assert.Nil(deliverWebhooks(log.NewNopLogger(), now, "", 0))
+	deliveries, err := db.WebhookDeliveries(now.UnixNano(), webhookDeliveriesOnce)
+	assert.Nil(err)
+	assert.Equal(0, len(deliveries))
+
+	// the newer event is still delivered
+	assert.Nil(saveEvent("", eventNodeUnreachable, NodeEvent{NodeAddr: dead.URL, Error: "refused"}, []string{server.URL}))
+	assert.Nil(deliverWebhooks(log.NewNopLogger(), now, "", 0))
+	assert.Equal(int32(1), atomic.LoadInt32(&posts))
+	deliveries, err = db.WebhookDeliveries(math.MaxInt64, 2*webhookDeliveriesOnce)
+	assert.Nil(err)
+	assert.Equal(webhookDeliveriesOnce+10, len(deliveries))
+}
+
+func TestWebhookBackoff(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(time.Second, webhookBackoff(1))
+	assert.Equal(2*time.Second, webhookBackoff(2))
+	assert.Equal(8*time.Second, webhookBackoff(4))
+	assert.Equal(webhookMaxBackoff, webhookBackoff(100))
+}
@@ -0,0 +1,210 @@
+package rpc
+
+import (
+	"bcXwallet/common"
+	rpcserver "common/rpc/lib/server"
+	rpctypes "common/rpc/lib/types"
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/tendermint/go-amino"
+	"github.com/tendermint/tmlibs/log"
+	tmpubsub "github.com/tendermint/tmlibs/pubsub"
+)
+
+// prefixes of websocket topics
+const (
+	topicNewBlock = "newBlock"
+	topicWalletTx = "walletTx:"
+	topicTxStatus = "txStatus:"
+)
+
+const (
+	websocketBlocksOnce = 20 // max count of blocks published in one round
+	subscribeTimeout    = 5 * time.Second
+	topicTag            = "topic"
+	chainTag            = "chain"
+)
+
+var eventBus = tmpubsub.NewServer()
+
+// topicQuery - query of pubsub matches messages of chain published with the same topic tag
+type topicQuery struct {
+	chain string
+	topic string
+}
+
+func (q topicQuery) Matches(tags tmpubsub.TagMap) bool {
+	chain, ok := tags.Get(chainTag)
+	if !ok || chain != q.chain {
+		return false
+	}
+
+	topic, ok := tags.Get(topicTag)
+	return ok && topic == q.topic
+}
+
+func (q topicQuery) String() string {
+	return q.chain + "/" + q.topic
+}
+
+// RegisterWebsocket - handle /websocket with all routes, and start publishing new blocks of every chain to subscribers
+func RegisterWebsocket(mux *http.ServeMux, cdc *amino.Codec, logger log.Logger) error {
+	if err := eventBus.Start(); err != nil {
+		return err
+	}
+
+	wm := rpcserver.NewWebsocketManager(Routes, cdc, rpcserver.EventSubscriber(eventBus))
+	wm.SetLogger(logger)
+	mux.HandleFunc("/websocket", wm.WebsocketHandler)
+
+	for _, c := range common.Chains() {
+		go followBlocks(c, "websocket publisher", publishBlocks(c))
+	}
+
+	return nil
+}
+
+// publishBlocks - scan function of followBlocks, publish blocks of chain after the height when it started
+func publishBlocks(c *common.Chain) func() (bool, error) {
+	publishedHeight := int64(0)
+
+	return func() (bool, error) {
+		if publishedHeight == 0 {
+			blkHeight, err := blockHeight(c)
+			if err != nil {
+				return false, err
+			}
+			publishedHeight = blkHeight.LastBlock
+		}
+
+		return scanBlocks(c, websocketBlocksOnce, func() (int64, error) { return publishedHeight, nil }, func(blk *BlockResult) error {
+			publishBlock(c, blk)
+			publishedHeight = blk.BlockHeight
+			return nil
+		})
+	}
+}
+
+// publishBlock - publish block of chain to newBlock topic, and each transaction to topics of its hash and addresses
+func publishBlock(c *common.Chain, blk *BlockResult) {
+	ctx := context.Background()
+	publish := func(msg interface{}, topic string) {
+		tags := tmpubsub.NewTagMap(map[string]interface{}{chainTag: c.Name, topicTag: topic})
+		if err := eventBus.PublishWithTags(ctx, msg, tags); err != nil {
+			common.GetLogger().Error("Cannot publish to websocket", "chain", c.Name, "topic", topic, "error", err)
+		}
+	}
+
+	publish(blk, topicNewBlock)
+
+	for i := range blk.Txs {
+		tx := &blk.Txs[i]
+		publish(tx, topicTxStatus+strings.ToLower(tx.TxHash))
+
+		for address := range txAddresses(tx) {
+			publish(tx, topicWalletTx+address)
+		}
+	}
+}
+
+// txAddresses - addresses of sender, receivers of messages and parties of transfer receipts
+func txAddresses(tx *TxResult) map[string]bool {
+	addresses := map[string]bool{tx.From: true}
+
+	for _, msg := range tx.Messages {
+		if msg.To != "" {
+			addresses[msg.To] = true
+		}
+	}
+
+	for _, receipt := range tx.Receipts {
+		if receipt.Name == receiptTransfer {
+			addresses[receipt.From] = true
+			addresses[receipt.To] = true
+		}
+	}
+	delete(addresses, "")
+
+	return addresses
+}
+
+// parseTopic - canonical topic, wallet name is replaced with its address and hash is in lower case with 0x
+func parseTopic(c *common.Chain, topic string) (string, error) {
+	switch {
+	case topic == topicNewBlock:
+		return topic, nil
+	case strings.HasPrefix(topic, topicWalletTx) && len(topic) > len(topicWalletTx):
+		wallet := topic[len(topicWalletTx):]
+		wallets, err := walletAddresses(c)
+		if err != nil {
+			return "", err
+		}
+		if address, ok := wallets[wallet]; ok {
+			wallet = address
+		}
+		return topicWalletTx + wallet, nil
+	case strings.HasPrefix(topic, topicTxStatus) && len(topic) > len(topicTxStatus):
+		hash := strings.ToLower(strings.TrimPrefix(topic[len(topicTxStatus):], "0x"))
+		return topicTxStatus + "0x" + hash, nil
+	default:
+		return "", errors.New("Unknown topic " + topic + ", it should be newBlock, walletTx:<name|address> or txStatus:<hash> ")
+	}
+}
+
+func subscribe(c *common.Chain, wsCtx rpctypes.WSRPCContext, topic string) (result *SubscribeResult, err error) {
+
+	query, err := parseTopic(c, topic)
+	if err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), subscribeTimeout)
+	defer cancel()
+
+	ch := make(chan interface{})
+	if err = wsCtx.GetEventSubscriber().Subscribe(ctx, wsCtx.GetRemoteAddr(), topicQuery{c.Name, query}, ch); err != nil {
+		return
+	}
+
+	go func() {
+		for msg := range ch {
+			event := &WebsocketEvent{Topic: topic, Chain: c.Name}
+			switch v := msg.(type) {
+			case *BlockResult:
+				event.Block = v
+			case *TxResult:
+				event.Tx = v
+			}
+			wsCtx.TryWriteRPCResponse(rpctypes.NewRPCSuccessResponse(wsCtx.Codec(), wsCtx.Request.ID+"#event", event))
+		}
+	}()
+
+	return &SubscribeResult{Topic: topic}, nil
+}
+
+func unsubscribe(c *common.Chain, wsCtx rpctypes.WSRPCContext, topic string) (result *SubscribeResult, err error) {
+
+	query, err := parseTopic(c, topic)
+	if err != nil {
+		return
+	}
+
+	if err = wsCtx.GetEventSubscriber().Unsubscribe(context.Background(), wsCtx.GetRemoteAddr(), topicQuery{c.Name, query}); err != nil {
+		return
+	}
+
+	return &SubscribeResult{Topic: topic}, nil
+}
+
+func unsubscribeAll(wsCtx rpctypes.WSRPCContext) (result *SubscribeResult, err error) {
+
+	if err = wsCtx.GetEventSubscriber().UnsubscribeAll(context.Background(), wsCtx.GetRemoteAddr()); err != nil {
+		return
+	}
+
+	return new(SubscribeResult), nil
+}
@@ -0,0 +1,103 @@
+package rpc
+
+import (
+	"bcXwallet/common"
+	"bcXwallet/common/config"
+	rpctypes "common/rpc/lib/types"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tendermint/go-amino"
+	tmpubsub "github.com/tendermint/tmlibs/pubsub"
+)
+
+// testWSConnection - websocket connection that collects responses
+type testWSConnection struct {
+	eventSub  rpctypes.EventSubscriber
+	responses chan rpctypes.RPCResponse
+}
+
+func (c *testWSConnection) GetRemoteAddr() string                      { return "127.0.0.1:1" }
+func (c *testWSConnection) WriteRPCResponse(resp rpctypes.RPCResponse) { c.responses <- resp }
+func (c *testWSConnection) TryWriteRPCResponse(resp rpctypes.RPCResponse) bool {
+	c.responses <- resp
+	return true
+}
+func (c *testWSConnection) GetEventSubscriber() rpctypes.EventSubscriber { return c.eventSub }
+func (c *testWSConnection) Codec() *amino.Codec                          { return amino.NewCodec() }
+
+func TestParseTopic(t *testing.T) {
+	assert := assert.New(t)
+	defer openTestDB(t)()
+
+	topic, err := parseTopic(testChain, "newBlock")
+	assert.Nil(err)
+	assert.Equal("newBlock", topic)
+
+	topic, err = parseTopic(testChain, "txStatus:ABCD")
+	assert.Nil(err)
+	assert.Equal("txStatus:0xabcd", topic)
+
+	topic, err = parseTopic(testChain, "walletTx:"+testAddress)
+	assert.Nil(err)
+	assert.Equal("walletTx:"+testAddress, topic)
+
+	_, err = parseTopic(testChain, "walletTx:")
+	assert.NotNil(err)
+
+	_, err = parseTopic(testChain, "unknown")
+	assert.NotNil(err)
+}
+
+func TestSubscribe(t *testing.T) {
+	assert := assert.New(t)
+	defer openTestDB(t)()
+
+	bus := tmpubsub.NewServer()
+	assert.Nil(bus.Start())
+	defer bus.Stop()
+
+	eventBus, bus = bus, eventBus
+	defer func() { eventBus = bus }()
+
+	conn := &testWSConnection{eventSub: eventBus, responses: make(chan rpctypes.RPCResponse, 10)}
+	wsCtx := rpctypes.WSRPCContext{Request: rpctypes.RPCRequest{ID: "1"}, WSRPCConnection: conn}
+
+	_, err := subscribe(testChain, wsCtx, "txStatus:0xAB")
+	assert.Nil(err)
+	_, err = subscribe(testChain, wsCtx, "walletTx:bob")
+	assert.Nil(err)
+
+	// blocks of other chains are not published to the subscriber
+	side := common.NewChain(config.ChainProfile{Name: "side", ChainID: testChainID})
+	publishBlock(side, &BlockResult{BlockHeight: 7, Txs: []TxResult{{TxHash: "0xab", From: "bob"}}})
+
+	publishBlock(testChain, &BlockResult{
+		BlockHeight: 5,
+		Txs: []TxResult{
+			{TxHash: "0xab", From: "alice", Messages: []Message{{To: "carol"}}},
+			{TxHash: "0xcd", From: "dave", Receipts: []Receipt{{Name: receiptTransfer, From: "contract", To: "bob"}}},
+		},
+	})
+
+	topics := make(map[string]string)
+	for i := 0; i < 2; i++ {
+		select {
+		case resp := <-conn.responses:
+			assert.Equal("1#event", resp.ID)
+			var event WebsocketEvent
+			assert.Nil(conn.Codec().UnmarshalJSON(resp.Result, &event))
+			assert.Equal(testChain.Name, event.Chain)
+			if assert.NotNil(event.Tx) {
+				topics[event.Topic] = event.Tx.TxHash
+			}
+		case <-time.After(time.Second):
+			t.Fatal("event is not received")
+		}
+	}
+	assert.Equal(map[string]string{"txStatus:0xAB": "0xab", "walletTx:bob": "0xcd"}, topics)
+
+	_, err = unsubscribeAll(wsCtx)
+	assert.Nil(err)
+}
@@ -6,6 +6,7 @@ import (
 	"blockchain/types"
 	"bytes"
 	"common/sig"
+	"strconv"
 	"strings"
 
 	"github.com/btcsuite/btcutil/base58"
@@ -14,41 +15,79 @@ import (
 )
 
 // TxParse 解析一笔交易（包含签名验证）的接口函数，将结果填入Transaction数据结构，其中Data字段为RLP编码的合约调用参数
+// 多重签名的交易返回第一个签名者（交易发起者）的公钥
 func TxParse(txString string) (tx types.Transaction, pubKey crypto.PubKeyEd25519, err error) {
+	return TxParseOfChain(chainID, txString)
+}
+
+// TxParseOfChain 解析指定链的一笔交易，返回交易发起者的公钥
+func TxParseOfChain(chainID, txString string) (tx types.Transaction, pubKey crypto.PubKeyEd25519, err error) {
+	var pubKeys []crypto.PubKeyEd25519
+	tx, pubKeys, err = TxParseMultiSigOfChain(chainID, txString)
+	if err != nil {
+		return
+	}
+	pubKey = pubKeys[0]
+	return
+}
+
+// TxParseMultiSig 解析一笔包含<N>个签名的交易，验证所有签名，按签名顺序返回所有签名者的公钥
+func TxParseMultiSig(txString string) (tx types.Transaction, pubKeys []crypto.PubKeyEd25519, err error) {
+	return TxParseMultiSigOfChain(chainID, txString)
+}
+
+// TxParseMultiSigOfChain 解析指定链的一笔包含<N>个签名的交易，交易的链ID不匹配时返回错误
+func TxParseMultiSigOfChain(chainID, txString string) (tx types.Transaction, pubKeys []crypto.PubKeyEd25519, err error) {
 	MAC := chainID + "<tx>"
 	Version := "v2"
-	SignerNumber := "<1>"
 	strs := strings.Split(txString, ".")
 
-	if len(strs) != 5 {
+	if len(strs) < 5 {
 		err = errors.New("tx data error")
 		return
 	}
 
-	if strs[0] != MAC || strs[1] != Version || strs[3] != SignerNumber {
-		err = errors.New("tx data error")
+	signerNumber, err := parseSignerNumber(strs[3])
+	if err != nil {
 		return
 	}
 
-	txData := base58.Decode(strs[2])
-	sigBytes := base58.Decode(strs[4])
-
-	reader := bytes.NewReader(sigBytes)
-	var siginfo sig.Ed25519Sig
-	err = rlp.Decode(reader, &siginfo)
-	if err != nil {
+	if strs[0] != MAC || strs[1] != Version || len(strs) != 4+signerNumber {
+		err = errors.New("tx data error")
 		return
 	}
 
-	if !siginfo.PubKey.VerifyBytes(txData, siginfo.SigValue) {
-		err = errors.New("verify sig fail")
-		return
+	txData := base58.Decode(strs[2])
+
+	pubKeys = make([]crypto.PubKeyEd25519, 0, signerNumber)
+	for _, sigString := range strs[4:] {
+		reader := bytes.NewReader(base58.Decode(sigString))
+		var siginfo sig.Ed25519Sig
+		err = rlp.Decode(reader, &siginfo)
+		if err != nil {
+			return
+		}
+
+		if !siginfo.PubKey.VerifyBytes(txData, siginfo.SigValue) {
+			err = errors.New("verify sig fail")
+			return
+		}
+
+		for _, pubKey := range pubKeys {
+			if pubKey == siginfo.PubKey {
+				err = errors.New("duplicate signer")
+				return
+			}
+		}
+		pubKeys = append(pubKeys, siginfo.PubKey)
 	}
-	pubKey = siginfo.PubKey
 
 	//RLP解码Transaction结构
-	reader = bytes.NewReader(txData)
+	reader := bytes.NewReader(txData)
 	err = rlp.Decode(reader, &tx)
+	if err != nil {
+		return
+	}
 	if len(tx.Messages) > 2 {
 		err = errors.New("Up to two messages at one time")
 		return
@@ -60,6 +99,20 @@ func TxParse(txString string) (tx types.Transaction, pubKey crypto.PubKeyEd25519
 	return
 }
 
+// parseSignerNumber 解析签名者数量字段<N>
+func parseSignerNumber(s string) (int, error) {
+	if !strings.HasPrefix(s, "<") || !strings.HasSuffix(s, ">") {
+		return 0, errors.New("tx data error")
+	}
+
+	n, err := strconv.Atoi(s[1 : len(s)-1])
+	if err != nil || n < 1 {
+		return 0, errors.New("tx data error")
+	}
+
+	return n, nil
+}
+
 func QueryDataParse(chainID, txString string) (crypto.Address, types.Query, error) {
 	MAC := chainID + "<qy>"
 	Version := "v1"
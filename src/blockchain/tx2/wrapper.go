@@ -4,6 +4,7 @@ import (
 	"common/sig"
 	"common/wal"
 	"encoding/hex"
+	"strconv"
 	"strings"
 
 	"blockchain/smcsdk/sdk/rlp"
@@ -66,8 +67,17 @@ func WrapPayload(nonce uint64, gasLimit int64, note string, messages ...types.Me
 // name:password
 // enprivatekey:password
 // 0x十六进制表示的私钥数据
-//nolint unhandled
 func WrapTx(payload []byte, privateKey string) string {
+	return WrapTxOfChain(chainID, payload, privateKey)
+}
+
+// WrapTxOfChain - sign the payload to string of chain, the format of privateKey refer WrapTx
+func WrapTxOfChain(chainID string, payload []byte, privateKey string) string {
+	return WrapMultiSigTxOfChain(chainID, payload, SignPayload(payload, privateKey))
+}
+
+// SignPayload - sign the payload with privateKey, the format of privateKey refer WrapTx
+func SignPayload(payload []byte, privateKey string) sig.Ed25519Sig {
 	var sigInfo sig.Ed25519Sig
 	var isHexPrivKey = strings.HasPrefix(privateKey, "0x")
 	var segPrivKey = strings.Split(privateKey, ":")
@@ -103,19 +113,65 @@ func WrapTx(payload []byte, privateKey string) string {
 		panic("Invalid private key format")
 	}
 
-	size, r, err := rlp.EncodeToReader(sigInfo)
+	return sigInfo
+}
+
+// WrapMultiSigTx - wrap the payload and signatures of all signers to string,
+// the format is MAC.Version.Payload.<N>.Signature1...SignatureN, the first signer is the sender
+func WrapMultiSigTx(payload []byte, sigInfos ...sig.Ed25519Sig) string {
+	return WrapMultiSigTxOfChain(chainID, payload, sigInfos...)
+}
+
+// WrapMultiSigTxOfChain - wrap the payload and signatures of all signers to string of chain
+func WrapMultiSigTxOfChain(chainID string, payload []byte, sigInfos ...sig.Ed25519Sig) string {
+	if len(sigInfos) == 0 {
+		panic("No signature of transaction")
+	}
+
+	sigStrings := make([]string, 0, len(sigInfos))
+	for _, sigInfo := range sigInfos {
+		size, r, err := rlp.EncodeToReader(sigInfo)
+		if err != nil {
+			panic(err.Error())
+		}
+		sig := make([]byte, size)
+		r.Read(sig)
+
+		sigStrings = append(sigStrings, base58.Encode(sig))
+	}
+
+	payloadString := base58.Encode(payload)
+
+	MAC := chainID + "<tx>"
+	Version := "v2"
+	SignerNumber := "<" + strconv.Itoa(len(sigInfos)) + ">"
+
+	return MAC + "." + Version + "." + payloadString + "." + SignerNumber + "." + strings.Join(sigStrings, ".")
+}
+
+// WrapQuery - sign the query key to string, the format of privateKey refer WrapTx
+// format: MAC.Version.Payload.<1>.Signature
+func WrapQuery(queryKey string, privateKey string) string {
+	return WrapQueryOfChain(chainID, queryKey, privateKey)
+}
+
+// WrapQueryOfChain - sign the query key to string of chain, the format of privateKey refer WrapTx
+func WrapQueryOfChain(chainID, queryKey string, privateKey string) string {
+	payload, err := rlp.EncodeToBytes(types.Query{QueryKey: queryKey})
+	if err != nil {
+		panic(err.Error())
+	}
+
+	size, r, err := rlp.EncodeToReader(SignPayload(payload, privateKey))
 	if err != nil {
 		panic(err.Error())
 	}
 	sig := make([]byte, size)
 	r.Read(sig)
 
-	payloadString := base58.Encode(payload)
-	sigString := base58.Encode(sig)
-
-	MAC := string(chainID) + "<tx>"
-	Version := "v2"
+	MAC := chainID + "<qy>"
+	Version := "v1"
 	SignerNumber := "<1>"
 
-	return MAC + "." + Version + "." + payloadString + "." + SignerNumber + "." + sigString
+	return MAC + "." + Version + "." + base58.Encode(payload) + "." + SignerNumber + "." + base58.Encode(sig)
 }
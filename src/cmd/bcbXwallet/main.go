@@ -31,6 +31,11 @@ func main() {
 			panic(err)
 		}
 
+		err = rpc.StartServices()
+		if err != nil {
+			panic(err)
+		}
+
 		rpcLogger := common.GetLogger()
 
 		coreCodec := amino.NewCodec()
@@ -38,6 +43,11 @@ func main() {
 		mux := http.NewServeMux()
 
 		rpcserver.RegisterRPCFuncs(mux, rpc.Routes, coreCodec, rpcLogger)
+		err = rpc.RegisterWebsocket(mux, coreCodec, rpcLogger)
+		if err != nil {
+			cmn.Exit(err.Error())
+		}
+
 		if common.GetConfig().UseHttps {
 			crtPath, keyPath := common.OutCertFileIsExist()
 			_, err = rpcserver.StartHTTPAndTLSServer(serverAddr(common.GetConfig().ServerAddr, false), mux, crtPath, keyPath, rpcLogger)
@@ -100,9 +110,13 @@ func version() string {
 var (
 	// global flags
 	flagRpcUrl string
+	flagChain  string
 
 	// block flag
-	flagHeight int64
+	flagHeight    int64
+	flagMinHeight int64
+	flagMaxHeight int64
+	flagDetail    bool
 
 	// transaction flag
 	flagTxHash string
@@ -111,10 +125,35 @@ var (
 	flagAddress      string
 	flagTokenAddress string
 	flagTokenName    string
+	flagTokenSymbol  string
+	flagTimestamp    int64
+
+	// contract flag
+	flagOrgID        string
+	flagContractName string
 
 	// commitTx flag
 	flagTx string
 
+	// signedQuery flag
+	flagKey string
+
+	// addressHistory flag
+	flagCursor string
+	flagLimit  int
+
+	// statement flag
+	flagFromTime int64
+	flagToTime   int64
+	flagFormat   string
+
+	// deposits flag
+	flagSinceID   uint64
+	flagDepositID uint64
+
+	// webhookReplay flag
+	flagEventID uint64
+
 	// wallet flag
 	flagName          string
 	flagPassword      string
@@ -128,12 +167,23 @@ var (
 	flagValue         string
 	flagPlainText     string
 	flagPageNum       uint64
+	flagAmountUnit    string
+	flagRequestID     string
+
+	// multisig flag
+	flagID        string
+	flagSigners   string
+	flagThreshold int
+	flagFile      string
 )
 
 var RootCmd = &cobra.Command{
 	Use:   "bcbXwallet",
 	Short: "bcb exchange wallet console",
 	Long:  "bcbXwallet client that it can perform the wallet operation, query chain information and so on.",
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		client.SetChain(flagChain)
+	},
 }
 
 func Execute() error {
@@ -143,21 +193,51 @@ func Execute() error {
 }
 
 func addFlags() {
+	RootCmd.PersistentFlags().StringVarP(&flagChain, "chain", "", "", "name of chain in config, the default chain if it's empty")
+
 	addWalletCreateFlag()
 	addWalletExportFlag()
 	addWalletImportFlag()
 	addWalletListFlag()
 	addTransferFlag()
 	addTransferOfflineFlag()
-
+	addMultisigCreateFlag()
+	addMultisigSignFlag()
+	addMultisigSignersFlag()
+	addMultisigExportFlag()
+	addMultisigImportFlag()
+	addMultisigFinalizeFlag()
+
+	addStatusFlag()
+	addChainVersionFlag()
+	addValidatorsFlag()
+	addNetInfoFlag()
 	addBlockHeightFlag()
 	addBlockFlag()
+	addBlocksFlag()
 	addTransactionFlag()
+	addPendingTxsFlag()
 	addBalanceFlag()
 	addBalanceOfTokenFlag()
 	addAllBalanceFlag()
+	addBalanceAtTimeFlag()
 	addNonceFlag()
+	addTokensFlag()
+	addTokenInfoFlag()
+	addContractFlag()
+	addContractVersionsFlag()
+	addOrganizationFlag()
 	addCommitTxFlag()
+	addAddressHistoryFlag()
+	addStatementFlag()
+	addReconcileReportFlag()
+	addDepositsFlag()
+	addDepositAckFlag()
+	addWebhookReplayFlag()
+	addMetaCacheStatsFlag()
+	addNodesFlag()
+	addSignedQueryFlag()
+	addConvertUnitFlag()
 }
 
 func addCommands() {
@@ -167,15 +247,43 @@ func addCommands() {
 	RootCmd.AddCommand(walletListCmd)
 	RootCmd.AddCommand(transferCmd)
 	RootCmd.AddCommand(transferOfflineCmd)
-
+	RootCmd.AddCommand(multisigCreateCmd)
+	RootCmd.AddCommand(multisigSignCmd)
+	RootCmd.AddCommand(multisigSignersCmd)
+	RootCmd.AddCommand(multisigExportCmd)
+	RootCmd.AddCommand(multisigImportCmd)
+	RootCmd.AddCommand(multisigFinalizeCmd)
+
+	RootCmd.AddCommand(statusCmd)
+	RootCmd.AddCommand(chainVersionCmd)
+	RootCmd.AddCommand(validatorsCmd)
+	RootCmd.AddCommand(netInfoCmd)
 	RootCmd.AddCommand(blockHeightCmd)
 	RootCmd.AddCommand(blockCmd)
+	RootCmd.AddCommand(blocksCmd)
 	RootCmd.AddCommand(transactionCmd)
+	RootCmd.AddCommand(pendingTxsCmd)
 	RootCmd.AddCommand(balanceCmd)
 	RootCmd.AddCommand(balanceOfTokenCmd)
 	RootCmd.AddCommand(allBalanceCmd)
+	RootCmd.AddCommand(balanceAtTimeCmd)
 	RootCmd.AddCommand(nonceCmd)
+	RootCmd.AddCommand(tokensCmd)
+	RootCmd.AddCommand(tokenInfoCmd)
+	RootCmd.AddCommand(contractCmd)
+	RootCmd.AddCommand(contractVersionsCmd)
+	RootCmd.AddCommand(organizationCmd)
 	RootCmd.AddCommand(commitTxCmd)
+	RootCmd.AddCommand(addressHistoryCmd)
+	RootCmd.AddCommand(statementCmd)
+	RootCmd.AddCommand(reconcileReportCmd)
+	RootCmd.AddCommand(depositsCmd)
+	RootCmd.AddCommand(depositAckCmd)
+	RootCmd.AddCommand(webhookReplayCmd)
+	RootCmd.AddCommand(metaCacheStatsCmd)
+	RootCmd.AddCommand(nodesCmd)
+	RootCmd.AddCommand(signedQueryCmd)
+	RootCmd.AddCommand(convertUnitCmd)
 }
 
 var walletCreateCmd = &cobra.Command{
@@ -252,7 +360,7 @@ var transferCmd = &cobra.Command{
 	Long:  "Transfer token to someone with value",
 	Args:  cobra.ExactArgs(0),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return client.Transfer(flagName, flagAccessKey, flagSmcAddress, flagGasLimit, flagNote, flagTo, flagValue, flagRpcUrl)
+		return client.Transfer(flagName, flagAccessKey, flagSmcAddress, flagGasLimit, flagNote, flagTo, flagValue, flagAmountUnit, flagRequestID, flagRpcUrl)
 	},
 }
 
@@ -264,6 +372,8 @@ func addTransferFlag() {
 	transferCmd.PersistentFlags().StringVarP(&flagNote, "note", "o", "", "note")
 	transferCmd.PersistentFlags().StringVarP(&flagTo, "to", "t", "", "to address")
 	transferCmd.PersistentFlags().StringVarP(&flagValue, "value", "v", "", "transfer value")
+	transferCmd.PersistentFlags().StringVarP(&flagAmountUnit, "amountUnit", "m", "cong", "unit of value, cong or token")
+	transferCmd.PersistentFlags().StringVarP(&flagRequestID, "requestId", "r", "", "client request id, repeat with the same id returns the original result")
 	transferCmd.PersistentFlags().StringVarP(&flagRpcUrl, "url", "u", serverAddr(common.GetConfig().ServerAddr, true), usage)
 }
 
@@ -273,7 +383,7 @@ var transferOfflineCmd = &cobra.Command{
 	Long:  "Offline pack and sign transfer transaction",
 	Args:  cobra.ExactArgs(0),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return client.TransferOffline(flagName, flagAccessKey, flagSmcAddress, flagGasLimit, flagNote, flagTo, flagValue, flagNonce, flagRpcUrl)
+		return client.TransferOffline(flagName, flagAccessKey, flagSmcAddress, flagGasLimit, flagNote, flagTo, flagValue, flagAmountUnit, flagNonce, flagRpcUrl)
 	},
 }
 
@@ -286,9 +396,168 @@ func addTransferOfflineFlag() {
 	transferOfflineCmd.PersistentFlags().StringVarP(&flagNote, "note", "o", "", "note")
 	transferOfflineCmd.PersistentFlags().StringVarP(&flagTo, "to", "t", "", "to address")
 	transferOfflineCmd.PersistentFlags().StringVarP(&flagValue, "value", "v", "", "transfer value")
+	transferOfflineCmd.PersistentFlags().StringVarP(&flagAmountUnit, "amountUnit", "m", "cong", "unit of value, cong or token")
 	transferOfflineCmd.PersistentFlags().StringVarP(&flagRpcUrl, "url", "u", serverAddr(common.GetConfig().ServerAddr, true), usage)
 }
 
+var multisigCreateCmd = &cobra.Command{
+	Use:   "multisigCreate",
+	Short: "Create multi-signature transaction",
+	Long:  "Create multi-signature transfer transaction, the first signer is the sender",
+	Args:  cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return client.MultisigCreate(flagSmcAddress, flagGasLimit, flagNote, flagTo, flagValue, flagAmountUnit, flagNonce, flagSigners, flagThreshold, flagRpcUrl)
+	},
+}
+
+func addMultisigCreateFlag() {
+	multisigCreateCmd.PersistentFlags().StringVarP(&flagSmcAddress, "smcAddress", "s", "", "smart contract address")
+	multisigCreateCmd.PersistentFlags().StringVarP(&flagGasLimit, "gasLimit", "g", "5000", "gas limit ")
+	multisigCreateCmd.PersistentFlags().StringVarP(&flagNonce, "nonce", "c", "", "nonce, default is the next nonce of the first signer")
+	multisigCreateCmd.PersistentFlags().StringVarP(&flagNote, "note", "o", "", "note")
+	multisigCreateCmd.PersistentFlags().StringVarP(&flagTo, "to", "t", "", "to address")
+	multisigCreateCmd.PersistentFlags().StringVarP(&flagValue, "value", "v", "", "transfer value")
+	multisigCreateCmd.PersistentFlags().StringVarP(&flagAmountUnit, "amountUnit", "m", "cong", "unit of value, cong or token")
+	multisigCreateCmd.PersistentFlags().StringVarP(&flagSigners, "signers", "i", "", "addresses of signers separated by comma")
+	multisigCreateCmd.PersistentFlags().IntVarP(&flagThreshold, "threshold", "r", 1, "count of signatures required")
+	multisigCreateCmd.PersistentFlags().StringVarP(&flagRpcUrl, "url", "u", serverAddr(common.GetConfig().ServerAddr, true), usage)
+}
+
+var multisigSignCmd = &cobra.Command{
+	Use:   "multisigSign",
+	Short: "Sign multi-signature transaction",
+	Long:  "Sign multi-signature transaction with wallet",
+	Args:  cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return client.MultisigSign(flagName, flagAccessKey, flagID, flagRpcUrl)
+	},
+}
+
+func addMultisigSignFlag() {
+	multisigSignCmd.PersistentFlags().StringVarP(&flagName, "name", "n", "", "wallet name")
+	multisigSignCmd.PersistentFlags().StringVarP(&flagAccessKey, "accessKey", "a", "", "wallet accessKey")
+	multisigSignCmd.PersistentFlags().StringVarP(&flagID, "id", "d", "", "id of multi-signature transaction")
+	multisigSignCmd.PersistentFlags().StringVarP(&flagRpcUrl, "url", "u", serverAddr(common.GetConfig().ServerAddr, true), usage)
+}
+
+var multisigSignersCmd = &cobra.Command{
+	Use:   "multisigSigners",
+	Short: "Get signers of multi-signature transaction",
+	Long:  "Get signed and unsigned signers of multi-signature transaction",
+	Args:  cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return client.MultisigSigners(flagID, flagRpcUrl)
+	},
+}
+
+func addMultisigSignersFlag() {
+	multisigSignersCmd.PersistentFlags().StringVarP(&flagID, "id", "d", "", "id of multi-signature transaction")
+	multisigSignersCmd.PersistentFlags().StringVarP(&flagRpcUrl, "url", "u", serverAddr(common.GetConfig().ServerAddr, true), usage)
+}
+
+var multisigExportCmd = &cobra.Command{
+	Use:   "multisigExport",
+	Short: "Export multi-signature transaction",
+	Long:  "Export multi-signature transaction with collected signatures to file",
+	Args:  cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return client.MultisigExport(flagID, flagFile, flagRpcUrl)
+	},
+}
+
+func addMultisigExportFlag() {
+	multisigExportCmd.PersistentFlags().StringVarP(&flagID, "id", "d", "", "id of multi-signature transaction")
+	multisigExportCmd.PersistentFlags().StringVarP(&flagFile, "file", "f", "", "export file, print it if empty")
+	multisigExportCmd.PersistentFlags().StringVarP(&flagRpcUrl, "url", "u", serverAddr(common.GetConfig().ServerAddr, true), usage)
+}
+
+var multisigImportCmd = &cobra.Command{
+	Use:   "multisigImport",
+	Short: "Import multi-signature transaction",
+	Long:  "Import multi-signature transaction from file and merge its signatures",
+	Args:  cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return client.MultisigImport(flagFile, flagRpcUrl)
+	},
+}
+
+func addMultisigImportFlag() {
+	multisigImportCmd.PersistentFlags().StringVarP(&flagFile, "file", "f", "", "file exported by multisigExport")
+	multisigImportCmd.PersistentFlags().StringVarP(&flagRpcUrl, "url", "u", serverAddr(common.GetConfig().ServerAddr, true), usage)
+}
+
+var multisigFinalizeCmd = &cobra.Command{
+	Use:   "multisigFinalize",
+	Short: "Finalize multi-signature transaction",
+	Long:  "Assemble multi-signature transaction when threshold is reached, commit it with commitTx",
+	Args:  cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return client.MultisigFinalize(flagID, flagRpcUrl)
+	},
+}
+
+func addMultisigFinalizeFlag() {
+	multisigFinalizeCmd.PersistentFlags().StringVarP(&flagID, "id", "d", "", "id of multi-signature transaction")
+	multisigFinalizeCmd.PersistentFlags().StringVarP(&flagRpcUrl, "url", "u", serverAddr(common.GetConfig().ServerAddr, true), usage)
+}
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Get chain status",
+	Long:  "Get chain ID, chain version, latest block and sync state of node",
+	Args:  cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return client.Status(flagRpcUrl)
+	},
+}
+
+func addStatusFlag() {
+	statusCmd.PersistentFlags().StringVarP(&flagRpcUrl, "url", "u", serverAddr(common.GetConfig().ServerAddr, true), usage)
+}
+
+var chainVersionCmd = &cobra.Command{
+	Use:   "chainVersion",
+	Short: "Get chain version",
+	Long:  "Get chain version that transactions are encoded with, it's detected from nodes at runtime",
+	Args:  cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return client.ChainVersion(flagRpcUrl)
+	},
+}
+
+func addChainVersionFlag() {
+	chainVersionCmd.PersistentFlags().StringVarP(&flagRpcUrl, "url", "u", serverAddr(common.GetConfig().ServerAddr, true), usage)
+}
+
+var validatorsCmd = &cobra.Command{
+	Use:   "validators",
+	Short: "Get validators",
+	Long:  "Get validators of specific height",
+	Args:  cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return client.Validators(flagHeight, flagRpcUrl)
+	},
+}
+
+func addValidatorsFlag() {
+	validatorsCmd.PersistentFlags().Int64VarP(&flagHeight, "height", "t", 0, "block height, 0 means current height")
+	validatorsCmd.PersistentFlags().StringVarP(&flagRpcUrl, "url", "u", serverAddr(common.GetConfig().ServerAddr, true), usage)
+}
+
+var netInfoCmd = &cobra.Command{
+	Use:   "netInfo",
+	Short: "Get network info",
+	Long:  "Get listening state and peers of node",
+	Args:  cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return client.NetInfo(flagRpcUrl)
+	},
+}
+
+func addNetInfoFlag() {
+	netInfoCmd.PersistentFlags().StringVarP(&flagRpcUrl, "url", "u", serverAddr(common.GetConfig().ServerAddr, true), usage)
+}
+
 var blockHeightCmd = &cobra.Command{
 	Use:   "blockHeight",
 	Short: "Get current block height",
@@ -309,42 +578,79 @@ var blockCmd = &cobra.Command{
 	Long:  "Get block information with height, must great than zero",
 	Args:  cobra.ExactArgs(0),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return client.Block(flagHeight, flagRpcUrl)
+		return client.Block(flagHeight, flagAmountUnit, flagRpcUrl)
 	},
 }
 
 func addBlockFlag() {
 	blockCmd.PersistentFlags().Int64VarP(&flagHeight, "height", "t", 0, "block height")
+	blockCmd.PersistentFlags().StringVarP(&flagAmountUnit, "amountUnit", "m", "cong", "unit of amounts, cong or token")
 	blockCmd.PersistentFlags().StringVarP(&flagRpcUrl, "url", "u", serverAddr(common.GetConfig().ServerAddr, true), usage)
 }
 
+var blocksCmd = &cobra.Command{
+	Use:   "blocks",
+	Short: "Get blocks information",
+	Long:  "Get headers or decoded transactions of blocks in height range, query again from nextHeight if it is not zero",
+	Args:  cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return client.Blocks(flagMinHeight, flagMaxHeight, flagDetail, flagAmountUnit, flagRpcUrl)
+	},
+}
+
+func addBlocksFlag() {
+	blocksCmd.PersistentFlags().Int64VarP(&flagMinHeight, "minHeight", "i", 0, "min block height, default is 1")
+	blocksCmd.PersistentFlags().Int64VarP(&flagMaxHeight, "maxHeight", "x", 0, "max block height, default is current height")
+	blocksCmd.PersistentFlags().BoolVarP(&flagDetail, "detail", "d", false, "get decoded transactions of blocks")
+	blocksCmd.PersistentFlags().StringVarP(&flagAmountUnit, "amountUnit", "m", "cong", "unit of amounts, cong or token")
+	blocksCmd.PersistentFlags().StringVarP(&flagRpcUrl, "url", "u", serverAddr(common.GetConfig().ServerAddr, true), usage)
+}
+
 var transactionCmd = &cobra.Command{
 	Use:   "transaction",
 	Short: "Get transaction information",
 	Long:  "Get transaction information with txHash and cannot be empty",
 	Args:  cobra.ExactArgs(0),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return client.Transaction(flagTxHash, flagRpcUrl)
+		return client.Transaction(flagTxHash, flagAmountUnit, flagRpcUrl)
 	},
 }
 
 func addTransactionFlag() {
 	transactionCmd.PersistentFlags().StringVarP(&flagTxHash, "txHash", "t", "", "transaction's hash")
+	transactionCmd.PersistentFlags().StringVarP(&flagAmountUnit, "amountUnit", "m", "cong", "unit of amounts, cong or token")
 	transactionCmd.PersistentFlags().StringVarP(&flagRpcUrl, "url", "u", serverAddr(common.GetConfig().ServerAddr, true), usage)
 }
 
+var pendingTxsCmd = &cobra.Command{
+	Use:   "pendingTxs",
+	Short: "Get pending transactions",
+	Long:  "Get transactions in mempool sent from or to address or wallet, all wallets if address is empty",
+	Args:  cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return client.PendingTxs(flagAddress, flagRpcUrl)
+	},
+}
+
+func addPendingTxsFlag() {
+	pendingTxsCmd.PersistentFlags().StringVarP(&flagAddress, "address", "a", "", "account's address or wallet's name")
+	pendingTxsCmd.PersistentFlags().StringVarP(&flagRpcUrl, "url", "u", serverAddr(common.GetConfig().ServerAddr, true), usage)
+}
+
 var balanceCmd = &cobra.Command{
 	Use:   "balance",
 	Short: "Get balance information",
 	Long:  "Get balance of BCB token for specific address",
 	Args:  cobra.ExactArgs(0),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return client.Balance(flagAddress, flagRpcUrl)
+		return client.Balance(flagAddress, flagAmountUnit, flagHeight, flagRpcUrl)
 	},
 }
 
 func addBalanceFlag() {
 	balanceCmd.PersistentFlags().StringVarP(&flagAddress, "address", "a", "", "account's address")
+	balanceCmd.PersistentFlags().StringVarP(&flagAmountUnit, "amountUnit", "m", "cong", "unit of balance, cong or token")
+	balanceCmd.PersistentFlags().Int64Var(&flagHeight, "height", 0, "block height of balance, 0 means current height")
 	balanceCmd.PersistentFlags().StringVarP(&flagRpcUrl, "url", "u", serverAddr(common.GetConfig().ServerAddr, true), usage)
 }
 
@@ -354,7 +660,7 @@ var balanceOfTokenCmd = &cobra.Command{
 	Long:  "Get balance of specific token for specific address",
 	Args:  cobra.ExactArgs(0),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return client.BalanceOfToken(flagAddress, flagTokenAddress, flagTokenName, flagRpcUrl)
+		return client.BalanceOfToken(flagAddress, flagTokenAddress, flagTokenName, flagAmountUnit, flagHeight, flagRpcUrl)
 	},
 }
 
@@ -362,6 +668,8 @@ func addBalanceOfTokenFlag() {
 	balanceOfTokenCmd.PersistentFlags().StringVarP(&flagAddress, "address", "a", "", "account's address")
 	balanceOfTokenCmd.PersistentFlags().StringVarP(&flagTokenAddress, "tokenAddress", "t", "", "token's address")
 	balanceOfTokenCmd.PersistentFlags().StringVarP(&flagTokenName, "tokenName", "n", "", "token's address")
+	balanceOfTokenCmd.PersistentFlags().StringVarP(&flagAmountUnit, "amountUnit", "m", "cong", "unit of balance, cong or token")
+	balanceOfTokenCmd.PersistentFlags().Int64Var(&flagHeight, "height", 0, "block height of balance, 0 means current height")
 	balanceOfTokenCmd.PersistentFlags().StringVarP(&flagRpcUrl, "url", "u", serverAddr(common.GetConfig().ServerAddr, true), usage)
 }
 
@@ -371,41 +679,304 @@ var allBalanceCmd = &cobra.Command{
 	Long:  "Get balance of all tokens for specific address",
 	Args:  cobra.ExactArgs(0),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return client.AllBalance(flagAddress, flagRpcUrl)
+		return client.AllBalance(flagAddress, flagAmountUnit, flagHeight, flagRpcUrl)
 	},
 }
 
 func addAllBalanceFlag() {
 	allBalanceCmd.PersistentFlags().StringVarP(&flagAddress, "address", "a", "", "account's address")
+	allBalanceCmd.PersistentFlags().StringVarP(&flagAmountUnit, "amountUnit", "m", "cong", "unit of balance, cong or token")
+	allBalanceCmd.PersistentFlags().Int64Var(&flagHeight, "height", 0, "block height of balance, 0 means current height")
 	allBalanceCmd.PersistentFlags().StringVarP(&flagRpcUrl, "url", "u", serverAddr(common.GetConfig().ServerAddr, true), usage)
 }
 
+var balanceAtTimeCmd = &cobra.Command{
+	Use:   "balanceAtTime",
+	Short: "Get balance at time",
+	Long:  "Get balance of specific address and token at the last block not later than timestamp",
+	Args:  cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return client.BalanceAtTime(flagAddress, flagTokenAddress, flagTimestamp, flagAmountUnit, flagRpcUrl)
+	},
+}
+
+func addBalanceAtTimeFlag() {
+	balanceAtTimeCmd.PersistentFlags().StringVarP(&flagAddress, "address", "a", "", "account's address")
+	balanceAtTimeCmd.PersistentFlags().StringVarP(&flagTokenAddress, "token", "t", "", "token's address, empty means genesis token")
+	balanceAtTimeCmd.PersistentFlags().Int64VarP(&flagTimestamp, "timestamp", "s", 0, "unix timestamp in seconds")
+	balanceAtTimeCmd.PersistentFlags().StringVarP(&flagAmountUnit, "amountUnit", "m", "cong", "unit of balance, cong or token")
+	balanceAtTimeCmd.PersistentFlags().StringVarP(&flagRpcUrl, "url", "u", serverAddr(common.GetConfig().ServerAddr, true), usage)
+}
+
 var nonceCmd = &cobra.Command{
 	Use:   "nonce",
 	Short: "Get account nonce",
 	Long:  "Get the next usable nonce for specific address",
 	Args:  cobra.ExactArgs(0),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return client.Nonce(flagAddress, flagRpcUrl)
+		return client.Nonce(flagAddress, flagHeight, flagRpcUrl)
 	},
 }
 
 func addNonceFlag() {
 	nonceCmd.PersistentFlags().StringVarP(&flagAddress, "address", "a", "", "account's address")
+	nonceCmd.PersistentFlags().Int64Var(&flagHeight, "height", 0, "block height of nonce, 0 means current height")
 	nonceCmd.PersistentFlags().StringVarP(&flagRpcUrl, "url", "u", serverAddr(common.GetConfig().ServerAddr, true), usage)
 }
 
+var tokensCmd = &cobra.Command{
+	Use:   "tokens",
+	Short: "Get all tokens",
+	Long:  "Get all tokens of chain with their current contract",
+	Args:  cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return client.Tokens(flagRpcUrl)
+	},
+}
+
+func addTokensFlag() {
+	tokensCmd.PersistentFlags().StringVarP(&flagRpcUrl, "url", "u", serverAddr(common.GetConfig().ServerAddr, true), usage)
+}
+
+var tokenInfoCmd = &cobra.Command{
+	Use:   "tokenInfo",
+	Short: "Get token info",
+	Long:  "Get token info with specific token address, name or symbol",
+	Args:  cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return client.TokenInfo(flagTokenAddress, flagTokenName, flagTokenSymbol, flagRpcUrl)
+	},
+}
+
+func addTokenInfoFlag() {
+	tokenInfoCmd.PersistentFlags().StringVarP(&flagTokenAddress, "tokenAddress", "t", "", "token's address")
+	tokenInfoCmd.PersistentFlags().StringVarP(&flagTokenName, "tokenName", "n", "", "token's name")
+	tokenInfoCmd.PersistentFlags().StringVarP(&flagTokenSymbol, "tokenSymbol", "s", "", "token's symbol")
+	tokenInfoCmd.PersistentFlags().StringVarP(&flagRpcUrl, "url", "u", serverAddr(common.GetConfig().ServerAddr, true), usage)
+}
+
+var contractCmd = &cobra.Command{
+	Use:   "contract",
+	Short: "Get contract",
+	Long:  "Get contract with specific address and its version that is effective at height",
+	Args:  cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return client.Contract(flagAddress, flagHeight, flagRpcUrl)
+	},
+}
+
+func addContractFlag() {
+	contractCmd.PersistentFlags().StringVarP(&flagAddress, "address", "a", "", "contract's address")
+	contractCmd.PersistentFlags().Int64VarP(&flagHeight, "height", "t", 0, "block height, 0 means current height")
+	contractCmd.PersistentFlags().StringVarP(&flagRpcUrl, "url", "u", serverAddr(common.GetConfig().ServerAddr, true), usage)
+}
+
+var contractVersionsCmd = &cobra.Command{
+	Use:   "contractVersions",
+	Short: "Get contract versions",
+	Long:  "Get all versions of contract with specific organization ID and name",
+	Args:  cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return client.ContractVersions(flagOrgID, flagContractName, flagHeight, flagRpcUrl)
+	},
+}
+
+func addContractVersionsFlag() {
+	contractVersionsCmd.PersistentFlags().StringVarP(&flagOrgID, "orgID", "o", "", "organization ID")
+	contractVersionsCmd.PersistentFlags().StringVarP(&flagContractName, "name", "n", "", "contract's name")
+	contractVersionsCmd.PersistentFlags().Int64VarP(&flagHeight, "height", "t", 0, "block height, 0 means current height")
+	contractVersionsCmd.PersistentFlags().StringVarP(&flagRpcUrl, "url", "u", serverAddr(common.GetConfig().ServerAddr, true), usage)
+}
+
+var organizationCmd = &cobra.Command{
+	Use:   "organization",
+	Short: "Get organization",
+	Long:  "Get organization with specific ID",
+	Args:  cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return client.Organization(flagOrgID, flagRpcUrl)
+	},
+}
+
+func addOrganizationFlag() {
+	organizationCmd.PersistentFlags().StringVarP(&flagOrgID, "orgID", "o", "", "organization ID")
+	organizationCmd.PersistentFlags().StringVarP(&flagRpcUrl, "url", "u", serverAddr(common.GetConfig().ServerAddr, true), usage)
+}
+
 var commitTxCmd = &cobra.Command{
 	Use:   "commitTx",
 	Short: "Commit transaction",
 	Long:  "Commit transaction with tx's data",
 	Args:  cobra.ExactArgs(0),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return client.CommitTx(flagTx, flagRpcUrl)
+		return client.CommitTx(flagTx, flagRequestID, flagRpcUrl)
 	},
 }
 
 func addCommitTxFlag() {
 	commitTxCmd.PersistentFlags().StringVarP(&flagTx, "tx", "t", "", "packed and signed transaction's data")
+	commitTxCmd.PersistentFlags().StringVarP(&flagRequestID, "requestId", "r", "", "client request id, repeat with the same id returns the original result")
 	commitTxCmd.PersistentFlags().StringVarP(&flagRpcUrl, "url", "u", serverAddr(common.GetConfig().ServerAddr, true), usage)
 }
+
+var addressHistoryCmd = &cobra.Command{
+	Use:   "addressHistory",
+	Short: "Get address history",
+	Long:  "Get transfers and fees of address from local indexer, query again with nextCursor if it is not empty",
+	Args:  cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return client.AddressHistory(flagAddress, flagTokenAddress, flagMinHeight, flagMaxHeight, flagCursor, flagLimit, flagRpcUrl)
+	},
+}
+
+func addAddressHistoryFlag() {
+	addressHistoryCmd.PersistentFlags().StringVarP(&flagAddress, "address", "a", "", "account's address")
+	addressHistoryCmd.PersistentFlags().StringVarP(&flagTokenAddress, "token", "t", "", "token address, empty means all tokens")
+	addressHistoryCmd.PersistentFlags().Int64VarP(&flagMinHeight, "fromHeight", "i", 0, "from block height")
+	addressHistoryCmd.PersistentFlags().Int64VarP(&flagMaxHeight, "toHeight", "x", 0, "to block height, 0 means no limit")
+	addressHistoryCmd.PersistentFlags().StringVarP(&flagCursor, "cursor", "c", "", "cursor of next page")
+	addressHistoryCmd.PersistentFlags().IntVarP(&flagLimit, "limit", "l", 100, "max count of items")
+	addressHistoryCmd.PersistentFlags().StringVarP(&flagRpcUrl, "url", "u", serverAddr(common.GetConfig().ServerAddr, true), usage)
+}
+
+var statementCmd = &cobra.Command{
+	Use:   "statement",
+	Short: "Get statement",
+	Long:  "Get statement of address in token between fromTime and toTime as CSV or JSON Lines, with opening and closing balance",
+	Args:  cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return client.Statement(flagAddress, flagTokenAddress, flagFromTime, flagToTime, flagFormat, flagFile, flagRpcUrl)
+	},
+}
+
+func addStatementFlag() {
+	statementCmd.PersistentFlags().StringVarP(&flagAddress, "address", "a", "", "account's address")
+	statementCmd.PersistentFlags().StringVarP(&flagTokenAddress, "token", "t", "", "token address, empty means genesis token")
+	statementCmd.PersistentFlags().Int64VarP(&flagFromTime, "fromTime", "i", 0, "from unix timestamp in seconds")
+	statementCmd.PersistentFlags().Int64VarP(&flagToTime, "toTime", "x", 0, "to unix timestamp in seconds")
+	statementCmd.PersistentFlags().StringVarP(&flagFormat, "format", "m", "csv", "format of statement, csv or jsonl")
+	statementCmd.PersistentFlags().StringVarP(&flagFile, "file", "f", "", "export file, print it if empty")
+	statementCmd.PersistentFlags().StringVarP(&flagRpcUrl, "url", "u", serverAddr(common.GetConfig().ServerAddr, true), usage)
+}
+
+var reconcileReportCmd = &cobra.Command{
+	Use:   "reconcileReport",
+	Short: "Get reconcile report",
+	Long:  "Get balance snapshot of wallets at height compared with transfers and fees since the previous one",
+	Args:  cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return client.ReconcileReport(flagHeight, flagRpcUrl)
+	},
+}
+
+func addReconcileReportFlag() {
+	reconcileReportCmd.PersistentFlags().Int64VarP(&flagHeight, "height", "t", 0, "height of snapshot, 0 means the latest one")
+	reconcileReportCmd.PersistentFlags().StringVarP(&flagRpcUrl, "url", "u", serverAddr(common.GetConfig().ServerAddr, true), usage)
+}
+
+var depositsCmd = &cobra.Command{
+	Use:   "deposits",
+	Short: "Get deposits",
+	Long:  "Get deposits that are not acked from deposit watcher, query again with lastId as sinceId",
+	Args:  cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return client.Deposits(flagSinceID, flagLimit, flagRpcUrl)
+	},
+}
+
+func addDepositsFlag() {
+	depositsCmd.PersistentFlags().Uint64VarP(&flagSinceID, "sinceId", "s", 0, "get deposits with id greater than sinceId")
+	depositsCmd.PersistentFlags().IntVarP(&flagLimit, "limit", "l", 100, "max count of deposits")
+	depositsCmd.PersistentFlags().StringVarP(&flagRpcUrl, "url", "u", serverAddr(common.GetConfig().ServerAddr, true), usage)
+}
+
+var depositAckCmd = &cobra.Command{
+	Use:   "depositAck",
+	Short: "Ack deposit",
+	Long:  "Ack the confirmed deposit, it will not be returned by deposits again",
+	Args:  cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return client.DepositAck(flagDepositID, flagRpcUrl)
+	},
+}
+
+func addDepositAckFlag() {
+	depositAckCmd.PersistentFlags().Uint64VarP(&flagDepositID, "id", "d", 0, "deposit id")
+	depositAckCmd.PersistentFlags().StringVarP(&flagRpcUrl, "url", "u", serverAddr(common.GetConfig().ServerAddr, true), usage)
+}
+
+var webhookReplayCmd = &cobra.Command{
+	Use:   "webhookReplay",
+	Short: "Replay webhook event",
+	Long:  "Deliver webhook event to all webhook urls again",
+	Args:  cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return client.WebhookReplay(flagEventID, flagRpcUrl)
+	},
+}
+
+func addWebhookReplayFlag() {
+	webhookReplayCmd.PersistentFlags().Uint64VarP(&flagEventID, "id", "d", 0, "webhook event id")
+	webhookReplayCmd.PersistentFlags().StringVarP(&flagRpcUrl, "url", "u", serverAddr(common.GetConfig().ServerAddr, true), usage)
+}
+
+var metaCacheStatsCmd = &cobra.Command{
+	Use:   "metaCacheStats",
+	Short: "Get metadata cache stats",
+	Long:  "Get size and hit/miss counters of contract and token metadata cache",
+	Args:  cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return client.MetaCacheStats(flagRpcUrl)
+	},
+}
+
+func addMetaCacheStatsFlag() {
+	metaCacheStatsCmd.PersistentFlags().StringVarP(&flagRpcUrl, "url", "u", serverAddr(common.GetConfig().ServerAddr, true), usage)
+}
+
+var nodesCmd = &cobra.Command{
+	Use:   "nodes",
+	Short: "Get nodes",
+	Long:  "Get health, latency and circuit state of nodes in pool",
+	Args:  cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return client.Nodes(flagRpcUrl)
+	},
+}
+
+func addNodesFlag() {
+	nodesCmd.PersistentFlags().StringVarP(&flagRpcUrl, "url", "u", serverAddr(common.GetConfig().ServerAddr, true), usage)
+}
+
+var signedQueryCmd = &cobra.Command{
+	Use:   "signedQuery",
+	Short: "Query with signature",
+	Long:  "Query state of key with the query signed by wallet",
+	Args:  cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return client.SignedQuery(flagName, flagAccessKey, flagKey, flagRpcUrl)
+	},
+}
+
+func addSignedQueryFlag() {
+	signedQueryCmd.PersistentFlags().StringVarP(&flagName, "name", "n", "", "wallet name")
+	signedQueryCmd.PersistentFlags().StringVarP(&flagAccessKey, "accessKey", "a", "", "wallet accessKey")
+	signedQueryCmd.PersistentFlags().StringVarP(&flagKey, "key", "k", "", "key of state")
+	signedQueryCmd.PersistentFlags().StringVarP(&flagRpcUrl, "url", "u", serverAddr(common.GetConfig().ServerAddr, true), usage)
+}
+
+var convertUnitCmd = &cobra.Command{
+	Use:   "convertUnit",
+	Short: "Convert amount unit",
+	Long:  "Convert amount between cong and token, 1 token = 1000000000 cong",
+	Args:  cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return client.ConvertUnit(flagValue, flagAmountUnit, flagRpcUrl)
+	},
+}
+
+func addConvertUnitFlag() {
+	convertUnitCmd.PersistentFlags().StringVarP(&flagValue, "value", "v", "", "amount value")
+	convertUnitCmd.PersistentFlags().StringVarP(&flagAmountUnit, "amountUnit", "m", "cong", "unit of value, cong or token")
+	convertUnitCmd.PersistentFlags().StringVarP(&flagRpcUrl, "url", "u", serverAddr(common.GetConfig().ServerAddr, true), usage)
+}
@@ -22,6 +22,12 @@ func main() {
 		panic(err)
 	}
 
+	err = rpc.StartServices()
+	if err != nil {
+		common.GetLogger().Error("start services failed", "error", err.Error())
+		panic(err)
+	}
+
 	rpcLogger := common.GetLogger()
 
 	coreCodec := amino.NewCodec()
@@ -29,6 +35,10 @@ func main() {
 	mux := http.NewServeMux()
 
 	rpcserver.RegisterRPCFuncs(mux, rpc.Routes, coreCodec, rpcLogger)
+	err = rpc.RegisterWebsocket(mux, coreCodec, rpcLogger)
+	if err != nil {
+		cmn.Exit(err.Error())
+	}
 
 	if common.GetConfig().UseHttps {
 		crtPath, keyPath := common.OutCertFileIsExist()
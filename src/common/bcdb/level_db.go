@@ -10,7 +10,9 @@ import (
 
 	"github.com/syndtr/goleveldb/leveldb"
 	"github.com/syndtr/goleveldb/leveldb/errors"
+	"github.com/syndtr/goleveldb/leveldb/iterator"
 	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/util"
 	"github.com/tendermint/tmlibs/log"
 )
 
@@ -121,6 +123,16 @@ func (db *GILevelDB) GetAllKey() []byte {
 	return []byte(keysBytes)
 }
 
+//遍历[start, limit)范围内的key，nil表示不限制
+func (db *GILevelDB) NewIterator(start, limit []byte) iterator.Iterator {
+	return db.db.NewIterator(&util.Range{Start: start, Limit: limit}, nil)
+}
+
+//遍历以prefix开头的key
+func (db *GILevelDB) NewPrefixIterator(prefix []byte) iterator.Iterator {
+	return db.db.NewIterator(util.BytesPrefix(prefix), nil)
+}
+
 func (db *GILevelDB) queryDB(w http.ResponseWriter, req *http.Request) {
 	value, err := db.Get([]byte(req.RequestURI))
 	if err != nil {
@@ -167,3 +179,8 @@ func (mBatch *GILevelDBBatch) Delete(key []byte) {
 func (mBatch *GILevelDBBatch) Commit() error {
 	return mBatch.db.db.Write(mBatch.batch, nil)
 }
+
+// Implements Batch.
+func (mBatch *GILevelDBBatch) CommitSync() error {
+	return mBatch.db.db.Write(mBatch.batch, &opt.WriteOptions{Sync: true})
+}
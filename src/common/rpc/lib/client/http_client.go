@@ -129,6 +129,14 @@ func NewJSONRPCClientEx(remote, certFile string, disableKeepAlive bool) *JSONRPC
 	}
 }
 
+// SetTimeout 设置请求的超时时间，以及连接节点的超时时间
+func (c *JSONRPCClient) SetTimeout(timeout, dialTimeout time.Duration) {
+	c.client.Timeout = timeout
+	if tr, ok := c.client.Transport.(*http.Transport); ok {
+		tr.DialContext = (&net.Dialer{Timeout: dialTimeout}).DialContext
+	}
+}
+
 func (c *JSONRPCClient) Call(method string, params map[string]interface{}, result interface{}) (interface{}, error) {
 	request, err := types.MapToRequest("jsonrpc-client", method, params)
 	if err != nil {